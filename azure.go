@@ -0,0 +1,153 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/exp/utf8string"
+)
+
+const (
+	// limit applies to the decoded binary array
+	azureMaxDataSize = 65535
+)
+
+type AzureProperty string
+
+const (
+	AzurePropertyCustomData AzureProperty = "customData"
+	AzurePropertyUserData   AzureProperty = "userData"
+)
+
+type AzureUserData struct {
+	UserData             Multipart
+	Property             AzureProperty
+	NormalizeLineEndings bool
+}
+
+func (a *AzureUserData) Encode() (string, error) {
+	if a.property() != AzurePropertyCustomData && a.property() != AzurePropertyUserData {
+		err := &Error{Op: "encode", Err: ErrInvalidType}
+		logger.Println("failed to encode azure user data", "func", getFuncName(), "property", a.Property, "error", err)
+		return "", err
+	}
+
+	var data []byte
+	if a.UserData != nil {
+		b, err := renderBytes(a.UserData)
+		if err != nil {
+			logger.Println("failed to encode azure user data", "func", getFuncName(), "property", a.Property, "error", err)
+			return "", err
+		}
+
+		data = b
+	}
+
+	// scripts authored on Windows fail with "bad interpreter" when CR survives
+	if a.NormalizeLineEndings {
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	}
+
+	if len(data) > azureMaxDataSize {
+		err := &Error{Op: "encode", Err: ErrPayloadTooLarge}
+		logger.Println("failed to encode azure user data", "func", getFuncName(), "property", a.Property, "size", len(data), "error", err)
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+func (a *AzureUserData) ARM() ([]byte, error) {
+	value, err := a.Encode()
+	if err != nil {
+		logger.Println("failed to build arm fragment", "func", getFuncName(), "property", a.Property, "error", err)
+		return nil, err
+	}
+
+	var doc any = map[string]string{string(AzurePropertyUserData): value}
+	if a.property() == AzurePropertyCustomData {
+		doc = map[string]any{"osProfile": map[string]string{string(AzurePropertyCustomData): value}}
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		err = &Error{Op: "encode", Err: err}
+		logger.Println("failed to build arm fragment", "func", getFuncName(), "property", a.Property, "error", err)
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (a *AzureUserData) Bicep() ([]byte, error) {
+	value, err := a.Encode()
+	if err != nil {
+		logger.Println("failed to build bicep fragment", "func", getFuncName(), "property", a.Property, "error", err)
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if a.property() == AzurePropertyCustomData {
+		fmt.Fprint(buf, "osProfile: {\n")
+		fmt.Fprintf(buf, "  customData: '%s'\n", value)
+		fmt.Fprint(buf, "}\n")
+	} else {
+		fmt.Fprintf(buf, "userData: '%s'\n", value)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (a *AzureUserData) Lint() []error {
+	warns := make([]error, 0)
+	if a.UserData == nil {
+		return warns
+	}
+
+	for _, p := range a.UserData.Parts() {
+		body := p.Body()
+		if !bytes.Contains(body, []byte("\r\n")) {
+			continue
+		}
+
+		// normalization only reaches bodies rendered as 7bit
+		spec, _ := lookupMediaType(p.MediaType())
+		if a.NormalizeLineEndings && spec.Encoding != EncodingBase64 && utf8string.NewString(string(body)).IsASCII() {
+			continue
+		}
+
+		warns = append(warns, &Error{Op: "lint", Err: ErrCRLFLineEndings})
+	}
+
+	return warns
+}
+
+func (a *AzureUserData) property() AzureProperty {
+	if a.Property == "" {
+		return AzurePropertyCustomData
+	}
+
+	return a.Property
+}
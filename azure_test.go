@@ -0,0 +1,289 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAzureUserData_Encode(t *testing.T) {
+	crlf := func() Multipart {
+		m, _ := NewMultipart()
+		m.Append(mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\r\n"+"echo hello\r\n")))
+		return m
+	}
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		azureData AzureUserData
+		expected  expected
+	}{
+		{
+			name: "positive case: crlf kept",
+			azureData: AzureUserData{
+				UserData: crlf(),
+			},
+			expected: expected{
+				res: "Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+					"Mime-Version: 1.0\r\n" +
+					"\r\n" +
+					"--+Go+User+Data+Boundary==\r\n" +
+					"Content-Transfer-Encoding: 7bit\r\n" +
+					"Content-Type: text/x-shellscript; charset=us-ascii\r\n" +
+					"\r\n" +
+					"#!/bin/sh\r\n" +
+					"echo hello\r\n" +
+					"\r\n" +
+					"\r\n" +
+					"--+Go+User+Data+Boundary==--\r\n",
+			},
+		},
+		{
+			name: "positive case: crlf normalized",
+			azureData: AzureUserData{
+				UserData:             crlf(),
+				NormalizeLineEndings: true,
+			},
+			expected: expected{
+				res: "Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\n" +
+					"Mime-Version: 1.0\n" +
+					"\n" +
+					"--+Go+User+Data+Boundary==\n" +
+					"Content-Transfer-Encoding: 7bit\n" +
+					"Content-Type: text/x-shellscript; charset=us-ascii\n" +
+					"\n" +
+					"#!/bin/sh\n" +
+					"echo hello\n" +
+					"\n" +
+					"\n" +
+					"--+Go+User+Data+Boundary==--\n",
+			},
+		},
+		{
+			name: "negative case: too large",
+			azureData: AzureUserData{
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+strings.Repeat("#", 65535))))
+					return m
+				}(),
+				Property: AzurePropertyUserData,
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrPayloadTooLarge},
+			},
+		},
+		{
+			name: "negative case: unknown property",
+			azureData: AzureUserData{
+				Property: "osProfile",
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrInvalidType},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.azureData.Encode()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(tt.expected.res)), actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestAzureUserData_ARM(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		azureData AzureUserData
+		expected  expected
+	}{
+		{
+			name: "positive case: customData",
+			azureData: AzureUserData{
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					return m
+				}(),
+			},
+			expected: expected{
+				res: `{"osProfile":{"customData":"Q29udGVudC1UeXBlOiBtdWx0aXBhcnQvbWl4ZWQ7IGJvdW5kYXJ5PSIrR28rVXNlcitEYXRhK0JvdW5kYXJ5PT0iDQpNaW1lLVZlcnNpb246IDEuMA0KDQotLStHbytVc2VyK0RhdGErQm91bmRhcnk9PS0tDQo="}}`,
+			},
+		},
+		{
+			name: "positive case: userData",
+			azureData: AzureUserData{
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					return m
+				}(),
+				Property: AzurePropertyUserData,
+			},
+			expected: expected{
+				res: `{"userData":"Q29udGVudC1UeXBlOiBtdWx0aXBhcnQvbWl4ZWQ7IGJvdW5kYXJ5PSIrR28rVXNlcitEYXRhK0JvdW5kYXJ5PT0iDQpNaW1lLVZlcnNpb246IDEuMA0KDQotLStHbytVc2VyK0RhdGErQm91bmRhcnk9PS0tDQo="}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.azureData.ARM()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, string(actual))
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestAzureUserData_Bicep(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		azureData AzureUserData
+		expected  expected
+	}{
+		{
+			name: "positive case: customData",
+			azureData: AzureUserData{
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					return m
+				}(),
+			},
+			expected: expected{
+				res: "osProfile: {\n" +
+					"  customData: 'Q29udGVudC1UeXBlOiBtdWx0aXBhcnQvbWl4ZWQ7IGJvdW5kYXJ5PSIrR28rVXNlcitEYXRhK0JvdW5kYXJ5PT0iDQpNaW1lLVZlcnNpb246IDEuMA0KDQotLStHbytVc2VyK0RhdGErQm91bmRhcnk9PS0tDQo='\n" +
+					"}\n",
+			},
+		},
+		{
+			name: "positive case: userData",
+			azureData: AzureUserData{
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					return m
+				}(),
+				Property: AzurePropertyUserData,
+			},
+			expected: expected{
+				res: "userData: 'Q29udGVudC1UeXBlOiBtdWx0aXBhcnQvbWl4ZWQ7IGJvdW5kYXJ5PSIrR28rVXNlcitEYXRhK0JvdW5kYXJ5PT0iDQpNaW1lLVZlcnNpb246IDEuMA0KDQotLStHbytVc2VyK0RhdGErQm91bmRhcnk9PS0tDQo='\n",
+			},
+		},
+		{
+			name: "negative case: unknown property",
+			azureData: AzureUserData{
+				Property: "osProfile",
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrInvalidType},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.azureData.Bicep()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, string(actual))
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestAzureUserData_Lint(t *testing.T) {
+	tests := []struct {
+		name      string
+		azureData AzureUserData
+		expected  []error
+	}{
+		{
+			name: "positive case: lf",
+			azureData: AzureUserData{
+				UserData: mustNewMultipart(mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\n"+"echo hello\n"))),
+			},
+			expected: []error{},
+		},
+		{
+			name: "positive case: crlf normalized",
+			azureData: AzureUserData{
+				UserData:             mustNewMultipart(mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\r\n"+"echo hello\r\n"))),
+				NormalizeLineEndings: true,
+			},
+			expected: []error{},
+		},
+		{
+			name: "negative case: crlf",
+			azureData: AzureUserData{
+				UserData: mustNewMultipart(mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\r\n"+"echo hello\r\n"))),
+			},
+			expected: []error{&Error{Op: "lint", Err: ErrCRLFLineEndings}},
+		},
+		{
+			name: "negative case: crlf in base64 part",
+			azureData: AzureUserData{
+				UserData:             mustNewMultipart(mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\r\n"+"echo 'こんにちは'\r\n"))),
+				NormalizeLineEndings: true,
+			},
+			expected: []error{&Error{Op: "lint", Err: ErrCRLFLineEndings}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.azureData.Lint())
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	boothookMarker      = "#cloud-boothook"
+	boothookInterpreter = "/bin/sh"
+	boothookDelimiter   = "GOUSERDATA_BOOTHOOK_EOF"
+)
+
+var (
+	boothookNameRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+type Boothook struct {
+	Frequency Frequency
+	Name      string
+	Body      []byte
+}
+
+func (b *Boothook) Build() (Part, error) {
+	shebang, body := b.split()
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "%s\n", boothookMarker)
+
+	switch b.Frequency {
+	case FrequencyAlways, FrequencyPerBoot:
+		fmt.Fprintf(buf, "%s\n", shebang)
+		buf.Write(body)
+
+		return NewPart(MediaTypeCloudBoothook, buf.Bytes())
+	case FrequencyPerInstance, FrequencyOnce:
+	default:
+		err := &Error{Op: "build", Err: ErrInvalidFrequency}
+		logger.Println("failed to build boothook", "func", getFuncName(), "boothook", b, "error", err)
+		return nil, err
+	}
+
+	if err := b.validate(shebang, body); err != nil {
+		logger.Println("failed to build boothook", "func", getFuncName(), "boothook", b, "error", err)
+		return nil, err
+	}
+
+	interpreter := strings.TrimPrefix(shebang, "#!")
+	fmt.Fprintf(buf, "#!%s\n", boothookInterpreter)
+
+	if b.Frequency == FrequencyOnce {
+		fmt.Fprintf(buf, "cloud-init-per once %s %s -s <<'%s'\n", b.Name, interpreter, boothookDelimiter)
+		writeHeredoc(buf, body, boothookDelimiter)
+
+		return NewPart(MediaTypeCloudBoothook, buf.Bytes())
+	}
+
+	fmt.Fprintf(buf, "sem=\"/var/lib/cloud/instances/${INSTANCE_ID:?}/sem/boothook.%s\"\n", b.Name)
+	fmt.Fprint(buf, "[ -e \"${sem}\" ] && exit 0\n")
+	fmt.Fprintf(buf, "%s -s <<'%s' || exit $?\n", interpreter, boothookDelimiter)
+	writeHeredoc(buf, body, boothookDelimiter)
+	fmt.Fprint(buf, "mkdir -p \"${sem%/*}\" && touch \"${sem}\"\n")
+
+	return NewPart(MediaTypeCloudBoothook, buf.Bytes())
+}
+
+func (b *Boothook) split() (string, []byte) {
+	body := b.Body
+	if bytes.HasPrefix(body, []byte(boothookMarker)) {
+		_, rest, _ := bytes.Cut(body, []byte("\n"))
+		body = rest
+	}
+
+	shebang := "#!" + boothookInterpreter
+	if bytes.HasPrefix(body, []byte("#!")) {
+		line, rest, _ := bytes.Cut(body, []byte("\n"))
+		shebang = strings.TrimRight(string(line), "\r")
+		body = rest
+	}
+
+	return shebang, body
+}
+
+func (b *Boothook) validate(shebang string, body []byte) error {
+	if !boothookNameRe.MatchString(b.Name) {
+		return &Error{Op: "build", Err: ErrInvalidName}
+	}
+
+	if !isPOSIXShell(shebang) {
+		return &Error{Op: "build", Err: ErrInvalidShebang}
+	}
+
+	for _, line := range strings.Split(string(body), "\n") {
+		if strings.TrimRight(line, "\r") == boothookDelimiter {
+			return &Error{Op: "build", Err: ErrInvalidBody}
+		}
+	}
+
+	return nil
+}
+
+func writeHeredoc(buf *bytes.Buffer, body []byte, delimiter string) {
+	buf.Write(body)
+	if len(body) > 0 && !bytes.HasSuffix(body, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+
+	fmt.Fprintf(buf, "%s\n", delimiter)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoothook_Build(t *testing.T) {
+	type expected struct {
+		res Part
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		boothook Boothook
+		expected expected
+	}{
+		{
+			name: "positive case: per-boot",
+			boothook: Boothook{
+				Frequency: FrequencyPerBoot,
+				Body:      []byte("echo 'Hello World' > /tmp/hello\n"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypeCloudBoothook, []byte("#cloud-boothook\n"+
+					"#!/bin/sh\n"+
+					"echo 'Hello World' > /tmp/hello\n",
+				)),
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: per-boot with marker",
+			boothook: Boothook{
+				Frequency: FrequencyPerBoot,
+				Body:      []byte("#cloud-boothook\n" + "#!/bin/bash\n" + "echo 'Hello World' > /tmp/hello\n"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypeCloudBoothook, []byte("#cloud-boothook\n"+
+					"#!/bin/bash\n"+
+					"echo 'Hello World' > /tmp/hello\n",
+				)),
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: per-instance",
+			boothook: Boothook{
+				Frequency: FrequencyPerInstance,
+				Name:      "hello",
+				Body:      []byte("#!/bin/bash\n" + "echo 'Hello World' > /tmp/hello"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypeCloudBoothook, []byte("#cloud-boothook\n"+
+					"#!/bin/sh\n"+
+					"sem=\"/var/lib/cloud/instances/${INSTANCE_ID:?}/sem/boothook.hello\"\n"+
+					"[ -e \"${sem}\" ] && exit 0\n"+
+					"/bin/bash -s <<'GOUSERDATA_BOOTHOOK_EOF' || exit $?\n"+
+					"echo 'Hello World' > /tmp/hello\n"+
+					"GOUSERDATA_BOOTHOOK_EOF\n"+
+					"mkdir -p \"${sem%/*}\" && touch \"${sem}\"\n",
+				)),
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: once",
+			boothook: Boothook{
+				Frequency: FrequencyOnce,
+				Name:      "hello",
+				Body:      []byte("echo 'Hello World' > /tmp/hello\n"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypeCloudBoothook, []byte("#cloud-boothook\n"+
+					"#!/bin/sh\n"+
+					"cloud-init-per once hello /bin/sh -s <<'GOUSERDATA_BOOTHOOK_EOF'\n"+
+					"echo 'Hello World' > /tmp/hello\n"+
+					"GOUSERDATA_BOOTHOOK_EOF\n",
+				)),
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: invalid frequency",
+			boothook: Boothook{
+				Body: []byte("echo 'Hello World' > /tmp/hello\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidFrequency},
+			},
+		},
+		{
+			name: "negative case: invalid name",
+			boothook: Boothook{
+				Frequency: FrequencyOnce,
+				Name:      "hello world",
+				Body:      []byte("echo 'Hello World' > /tmp/hello\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidName},
+			},
+		},
+		{
+			name: "negative case: non-shell interpreter",
+			boothook: Boothook{
+				Frequency: FrequencyOnce,
+				Name:      "hello",
+				Body:      []byte("#!/usr/bin/python3\n" + "print('Hello World')\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidShebang},
+			},
+		},
+		{
+			name: "negative case: body contains delimiter",
+			boothook: Boothook{
+				Frequency: FrequencyPerInstance,
+				Name:      "hello",
+				Body:      []byte("echo 'Hello World'\n" + "GOUSERDATA_BOOTHOOK_EOF\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidBody},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.boothook.Build()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,466 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type BottlerocketMode string
+
+const (
+	BottlerocketModeAlways BottlerocketMode = "always"
+	BottlerocketModeOnce   BottlerocketMode = "once"
+	BottlerocketModeOff    BottlerocketMode = "off"
+)
+
+var (
+	bottlerocketSettingsKeys = []string{
+		"autoscaling",
+		"aws",
+		"boot",
+		"bootstrap-containers",
+		"cloudformation",
+		"container-registry",
+		"container-runtime",
+		"dns",
+		"ecs",
+		"host-containers",
+		"kernel",
+		"kubernetes",
+		"metrics",
+		"motd",
+		"network",
+		"ntp",
+		"oci-defaults",
+		"oci-hooks",
+		"pki",
+		"updates",
+	}
+
+	bottlerocketTaintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+	bottlerocketNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	tomlBareKeyRe      = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+type Bottlerocket struct {
+	Kubernetes          *BottlerocketKubernetes
+	HostContainers      map[string]BottlerocketHostContainer
+	BootstrapContainers map[string]BottlerocketBootstrapContainer
+	Settings            map[string]any
+}
+
+type BottlerocketKubernetes struct {
+	ClusterName        string
+	APIServer          string
+	ClusterCertificate string
+	ClusterDNSIP       string
+	MaxPods            int
+	NodeLabels         map[string]string
+	NodeTaints         map[string][]string
+}
+
+type BottlerocketHostContainer struct {
+	Source       string
+	Enabled      *bool
+	Superpowered *bool
+	UserData     Renderer
+}
+
+type BottlerocketBootstrapContainer struct {
+	Source    string
+	Mode      BottlerocketMode
+	Essential bool
+	UserData  Renderer
+}
+
+func (b *Bottlerocket) Render(w io.Writer) error {
+	if err := b.validate(); err != nil {
+		err = &Error{Op: "validate", Err: err}
+		logger.Println("failed to render bottlerocket settings", "func", getFuncName(), "bottlerocket", b, "error", err)
+		return err
+	}
+
+	settings, err := b.table()
+	if err != nil {
+		logger.Println("failed to render bottlerocket settings", "func", getFuncName(), "bottlerocket", b, "error", err)
+		return err
+	}
+
+	enc := &tomlEncoder{w: w}
+	if err := enc.table([]string{"settings"}, settings); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render bottlerocket settings", "func", getFuncName(), "bottlerocket", b, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (b *Bottlerocket) table() (map[string]any, error) {
+	settings := maps.Clone(b.Settings)
+	if settings == nil {
+		settings = make(map[string]any)
+	}
+
+	if k := b.Kubernetes; k != nil {
+		kubernetes := map[string]any{
+			"cluster-name":        k.ClusterName,
+			"api-server":          k.APIServer,
+			"cluster-certificate": k.ClusterCertificate,
+		}
+
+		if k.ClusterDNSIP != "" {
+			kubernetes["cluster-dns-ip"] = k.ClusterDNSIP
+		}
+
+		if k.MaxPods > 0 {
+			kubernetes["max-pods"] = k.MaxPods
+		}
+
+		if len(k.NodeLabels) > 0 {
+			kubernetes["node-labels"] = k.NodeLabels
+		}
+
+		if len(k.NodeTaints) > 0 {
+			kubernetes["node-taints"] = k.NodeTaints
+		}
+
+		settings["kubernetes"] = kubernetes
+	}
+
+	if len(b.HostContainers) > 0 {
+		containers := make(map[string]any, len(b.HostContainers))
+		for name, c := range b.HostContainers {
+			container := make(map[string]any)
+
+			if c.Source != "" {
+				container["source"] = c.Source
+			}
+
+			if c.Enabled != nil {
+				container["enabled"] = *c.Enabled
+			}
+
+			if c.Superpowered != nil {
+				container["superpowered"] = *c.Superpowered
+			}
+
+			if c.UserData != nil {
+				userData, err := renderBytes(c.UserData)
+				if err != nil {
+					return nil, err
+				}
+
+				container["user-data"] = base64.StdEncoding.EncodeToString(userData)
+			}
+
+			containers[name] = container
+		}
+
+		settings["host-containers"] = containers
+	}
+
+	if len(b.BootstrapContainers) > 0 {
+		containers := make(map[string]any, len(b.BootstrapContainers))
+		for name, c := range b.BootstrapContainers {
+			container := map[string]any{
+				"source":    c.Source,
+				"essential": c.Essential,
+			}
+
+			if c.Mode != "" {
+				container["mode"] = string(c.Mode)
+			}
+
+			if c.UserData != nil {
+				userData, err := renderBytes(c.UserData)
+				if err != nil {
+					return nil, err
+				}
+
+				container["user-data"] = base64.StdEncoding.EncodeToString(userData)
+			}
+
+			containers[name] = container
+		}
+
+		settings["bootstrap-containers"] = containers
+	}
+
+	return settings, nil
+}
+
+func (b *Bottlerocket) validate() error {
+	// free-form settings must not collide with the typed fields
+	typed := map[string]bool{
+		"kubernetes":           b.Kubernetes != nil,
+		"host-containers":      len(b.HostContainers) > 0,
+		"bootstrap-containers": len(b.BootstrapContainers) > 0,
+	}
+
+	for key := range b.Settings {
+		if !slices.Contains(bottlerocketSettingsKeys, key) {
+			return ErrUnknownSetting
+		}
+
+		if typed[key] {
+			return ErrInvalidConfiguration
+		}
+	}
+
+	if k := b.Kubernetes; k != nil {
+		if k.ClusterName == "" {
+			return ErrInvalidName
+		}
+
+		u, err := url.Parse(k.APIServer)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return ErrInvalidURL
+		}
+
+		if _, err := base64.StdEncoding.DecodeString(k.ClusterCertificate); err != nil || k.ClusterCertificate == "" {
+			return ErrInvalidCertificate
+		}
+
+		if k.ClusterDNSIP != "" && !validIP(k.ClusterDNSIP) {
+			return ErrInvalidIPAddress
+		}
+
+		for _, taints := range k.NodeTaints {
+			for _, taint := range taints {
+				i := strings.LastIndex(taint, ":")
+				if i < 0 || !slices.Contains(bottlerocketTaintEffects, taint[i+1:]) {
+					return ErrInvalidTaint
+				}
+			}
+		}
+	}
+
+	for name := range b.HostContainers {
+		if !bottlerocketNameRe.MatchString(name) {
+			return ErrInvalidName
+		}
+	}
+
+	for name, c := range b.BootstrapContainers {
+		if !bottlerocketNameRe.MatchString(name) {
+			return ErrInvalidName
+		}
+
+		if c.Source == "" {
+			return ErrMissingSource
+		}
+
+		switch c.Mode {
+		case "", BottlerocketModeAlways, BottlerocketModeOnce, BottlerocketModeOff:
+		default:
+			return ErrInvalidType
+		}
+	}
+
+	return nil
+}
+
+type tomlEncoder struct {
+	w       io.Writer
+	written bool
+}
+
+func (e *tomlEncoder) table(path []string, t map[string]any) error {
+	keys := maps.Keys(t)
+	slices.Sort(keys)
+
+	var values, tables []string
+	for _, key := range keys {
+		if isTOMLTable(t[key]) {
+			tables = append(tables, key)
+		} else {
+			values = append(values, key)
+		}
+	}
+
+	// nested empty tables still need a header, otherwise a container with nothing set would vanish
+	if len(values) > 0 || (len(tables) == 0 && len(path) > 1) {
+		header := make([]string, 0, len(path))
+		for _, p := range path {
+			header = append(header, tomlKey(p))
+		}
+
+		if e.written {
+			if _, err := fmt.Fprint(e.w, "\n"); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(e.w, "[%s]\n", strings.Join(header, ".")); err != nil {
+			return err
+		}
+
+		for _, key := range values {
+			v, err := tomlValue(t[key])
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintf(e.w, "%s = %s\n", tomlKey(key), v); err != nil {
+				return err
+			}
+		}
+
+		e.written = true
+	}
+
+	for _, key := range tables {
+		sub, err := tomlTable(t[key])
+		if err != nil {
+			return err
+		}
+
+		if err := e.table(append(slices.Clone(path), key), sub); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isTOMLTable(v any) bool {
+	switch v.(type) {
+	case map[string]any, map[string]string, map[string][]string:
+		return true
+	default:
+		return false
+	}
+}
+
+func tomlTable(v any) (map[string]any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		return v, nil
+	case map[string]string:
+		t := make(map[string]any, len(v))
+		for key, val := range v {
+			t[key] = val
+		}
+		return t, nil
+	case map[string][]string:
+		t := make(map[string]any, len(v))
+		for key, val := range v {
+			t[key] = val
+		}
+		return t, nil
+	default:
+		return nil, ErrInvalidType
+	}
+}
+
+func tomlValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return tomlString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", ErrInvalidType
+		}
+
+		s := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+		return s, nil
+	case []string:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, tomlString(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	default:
+		return "", ErrInvalidType
+	}
+}
+
+func tomlKey(key string) string {
+	if tomlBareKeyRe.MatchString(key) {
+		return key
+	}
+
+	return tomlString(key)
+}
+
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+
+	return b.String()
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBottlerocket_Render(t *testing.T) {
+	enabled := true
+
+	nested := func() Multipart {
+		m, _ := NewMultipart()
+		p, _ := NewPart(MediaTypeXShellscript, []byte("#!/bin/sh\necho hello\n"))
+		m.Append(p)
+		return m
+	}
+
+	nestedUserData, _ := renderBytes(nested())
+
+	kubernetes := &BottlerocketKubernetes{
+		ClusterName:        "cluster-0",
+		APIServer:          "https://example.com",
+		ClusterCertificate: "Zm9v",
+	}
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name         string
+		bottlerocket Bottlerocket
+		expected     expected
+	}{
+		{
+			name: "positive case: kubernetes",
+			bottlerocket: Bottlerocket{
+				Kubernetes: &BottlerocketKubernetes{
+					ClusterName:        "cluster-0",
+					APIServer:          "https://example.com",
+					ClusterCertificate: "Zm9v",
+					ClusterDNSIP:       "10.100.0.10",
+					MaxPods:            58,
+					NodeLabels:         map[string]string{"node.example.com/role": "worker"},
+					NodeTaints:         map[string][]string{"dedicated": {"gpu:NoSchedule"}},
+				},
+			},
+			expected: expected{
+				res: "[settings.kubernetes]\n" +
+					"api-server = \"https://example.com\"\n" +
+					"cluster-certificate = \"Zm9v\"\n" +
+					"cluster-dns-ip = \"10.100.0.10\"\n" +
+					"cluster-name = \"cluster-0\"\n" +
+					"max-pods = 58\n" +
+					"\n" +
+					"[settings.kubernetes.node-labels]\n" +
+					"\"node.example.com/role\" = \"worker\"\n" +
+					"\n" +
+					"[settings.kubernetes.node-taints]\n" +
+					"dedicated = [\"gpu:NoSchedule\"]\n",
+			},
+		},
+		{
+			name: "positive case: containers",
+			bottlerocket: Bottlerocket{
+				HostContainers: map[string]BottlerocketHostContainer{
+					"admin": {Enabled: &enabled},
+				},
+				BootstrapContainers: map[string]BottlerocketBootstrapContainer{
+					"setup": {
+						Source:    "public.ecr.aws/example/setup:latest",
+						Mode:      BottlerocketModeOnce,
+						Essential: true,
+						UserData:  nested(),
+					},
+				},
+			},
+			expected: expected{
+				res: "[settings.bootstrap-containers.setup]\n" +
+					"essential = true\n" +
+					"mode = \"once\"\n" +
+					"source = \"public.ecr.aws/example/setup:latest\"\n" +
+					"user-data = \"" + base64.StdEncoding.EncodeToString(nestedUserData) + "\"\n" +
+					"\n" +
+					"[settings.host-containers.admin]\n" +
+					"enabled = true\n",
+			},
+		},
+		{
+			name: "positive case: free-form settings",
+			bottlerocket: Bottlerocket{
+				Settings: map[string]any{
+					"motd": "hello \"world\"\n",
+					"ntp": map[string]any{
+						"time-servers": []string{"a.example.com", "b.example.com"},
+					},
+					"kernel": map[string]any{
+						"sysctl": map[string]string{"vm.max_map_count": "262144"},
+					},
+					"updates": map[string]any{
+						"ignore-waves": false,
+						"seed":         []any{int64(1), 2.5},
+					},
+				},
+			},
+			expected: expected{
+				res: "[settings]\n" +
+					"motd = \"hello \\\"world\\\"\\n\"\n" +
+					"\n" +
+					"[settings.kernel.sysctl]\n" +
+					"\"vm.max_map_count\" = \"262144\"\n" +
+					"\n" +
+					"[settings.ntp]\n" +
+					"time-servers = [\"a.example.com\", \"b.example.com\"]\n" +
+					"\n" +
+					"[settings.updates]\n" +
+					"ignore-waves = false\n" +
+					"seed = [1, 2.5]\n",
+			},
+		},
+		{
+			name:         "positive case: empty",
+			bottlerocket: Bottlerocket{},
+			expected: expected{
+				res: "",
+			},
+		},
+		{
+			name: "positive case: empty host container",
+			bottlerocket: Bottlerocket{
+				HostContainers: map[string]BottlerocketHostContainer{"admin": {}},
+			},
+			expected: expected{
+				res: "[settings.host-containers.admin]\n",
+			},
+		},
+		{
+			name: "negative case: unknown setting",
+			bottlerocket: Bottlerocket{
+				Settings: map[string]any{"unknown": "value"},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrUnknownSetting},
+			},
+		},
+		{
+			name: "negative case: setting shadows typed field",
+			bottlerocket: Bottlerocket{
+				Kubernetes: kubernetes,
+				Settings:   map[string]any{"kubernetes": map[string]any{"max-pods": 110}},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidConfiguration},
+			},
+		},
+		{
+			name: "negative case: invalid api server",
+			bottlerocket: Bottlerocket{
+				Kubernetes: &BottlerocketKubernetes{ClusterName: "cluster-0", APIServer: "http://example.com", ClusterCertificate: "Zm9v"},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidURL},
+			},
+		},
+		{
+			name: "negative case: invalid certificate",
+			bottlerocket: Bottlerocket{
+				Kubernetes: &BottlerocketKubernetes{ClusterName: "cluster-0", APIServer: "https://example.com", ClusterCertificate: "!"},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidCertificate},
+			},
+		},
+		{
+			name: "negative case: invalid taint effect",
+			bottlerocket: Bottlerocket{
+				Kubernetes: &BottlerocketKubernetes{
+					ClusterName:        "cluster-0",
+					APIServer:          "https://example.com",
+					ClusterCertificate: "Zm9v",
+					NodeTaints:         map[string][]string{"dedicated": {"gpu:Never"}},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidTaint},
+			},
+		},
+		{
+			name: "negative case: invalid container name",
+			bottlerocket: Bottlerocket{
+				HostContainers: map[string]BottlerocketHostContainer{"Admin": {Enabled: &enabled}},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidName},
+			},
+		},
+		{
+			name: "negative case: missing bootstrap source",
+			bottlerocket: Bottlerocket{
+				BootstrapContainers: map[string]BottlerocketBootstrapContainer{
+					"setup": {Mode: BottlerocketModeOnce},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrMissingSource},
+			},
+		},
+		{
+			name: "negative case: invalid bootstrap mode",
+			bottlerocket: Bottlerocket{
+				BootstrapContainers: map[string]BottlerocketBootstrapContainer{
+					"setup": {Source: "public.ecr.aws/example/setup:latest", Mode: "twice"},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidType},
+			},
+		},
+		{
+			name: "negative case: unsupported value",
+			bottlerocket: Bottlerocket{
+				Settings: map[string]any{"motd": struct{}{}},
+			},
+			expected: expected{
+				err: &Error{Op: "render", Err: ErrInvalidType},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			err := tt.bottlerocket.Render(w)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, w.String())
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@
 package userdata
 
 import (
+	"bytes"
 	"io"
 )
 
@@ -43,4 +44,48 @@ const (
 	MediaTypeXShellscriptPerBoot     MediaType = "text/x-shellscript-per-boot"
 	MediaTypeXShellscriptPerInstance MediaType = "text/x-shellscript-per-instance"
 	MediaTypeXShellscriptPerOnce     MediaType = "text/x-shellscript-per-once"
+	MediaTypeNodeEKSAWS              MediaType = "application/node.eks.aws"
 )
+
+var (
+	builtinMediaTypes = []MediaType{
+		MediaTypeCloudBoothook,
+		MediaTypeCloudConfig,
+		MediaTypeCloudConfigArchive,
+		MediaTypeCloudConfigJsonp,
+		MediaTypeJinja2,
+		MediaTypePartHandler,
+		MediaTypeXIncludeOnceUrl,
+		MediaTypeXIncludeUrl,
+		MediaTypeXShellscript,
+		MediaTypeXShellscriptPerBoot,
+		MediaTypeXShellscriptPerInstance,
+		MediaTypeXShellscriptPerOnce,
+		MediaTypeNodeEKSAWS,
+	}
+)
+
+type Encoding string
+
+const (
+	Encoding7bit   Encoding = "7bit"
+	EncodingBase64 Encoding = "base64"
+)
+
+type Frequency string
+
+const (
+	FrequencyAlways      Frequency = "always"
+	FrequencyPerBoot     Frequency = "per-boot"
+	FrequencyPerInstance Frequency = "once-per-instance"
+	FrequencyOnce        Frequency = "once"
+)
+
+func renderBytes(r Renderer) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := r.Render(buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
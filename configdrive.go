@@ -0,0 +1,243 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"regexp"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	configDriveVolumeID         = "config-2"
+	configDriveRoot             = "openstack"
+	configDriveLatest           = "latest"
+	configDriveUserData         = "user_data"
+	configDriveMetaData         = "meta_data.json"
+	configDriveNetworkData      = "network_data.json"
+	configDriveNetworkDataSince = "2015-10-15"
+	configDriveFileMode         = 0o644
+)
+
+var (
+	configDriveVersions = []string{"2012-08-10", "2013-04-04", "2013-10-17", "2015-10-15"}
+
+	configDriveVersionRe = regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+)
+
+type OpenStackMetaData struct {
+	UUID             string            `json:"uuid"`
+	Name             string            `json:"name,omitempty"`
+	Hostname         string            `json:"hostname,omitempty"`
+	AvailabilityZone string            `json:"availability_zone,omitempty"`
+	ProjectID        string            `json:"project_id,omitempty"`
+	LaunchIndex      int               `json:"launch_index"`
+	PublicKeys       map[string]string `json:"public_keys,omitempty"`
+	Keys             []OpenStackKey    `json:"keys,omitempty"`
+	Meta             map[string]string `json:"meta,omitempty"`
+}
+
+type OpenStackKey struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+type OpenStackNetworkData struct {
+	Links    []OpenStackLink    `json:"links,omitempty"`
+	Networks []OpenStackNetwork `json:"networks,omitempty"`
+	Services []OpenStackService `json:"services,omitempty"`
+}
+
+type OpenStackLink struct {
+	ID                 string   `json:"id"`
+	Type               string   `json:"type"`
+	EthernetMACAddress string   `json:"ethernet_mac_address,omitempty"`
+	MTU                int      `json:"mtu,omitempty"`
+	VIFID              string   `json:"vif_id,omitempty"`
+	BondLinks          []string `json:"bond_links,omitempty"`
+	BondMode           string   `json:"bond_mode,omitempty"`
+	VLANLink           string   `json:"vlan_link,omitempty"`
+	VLANID             int      `json:"vlan_id,omitempty"`
+	VLANMACAddress     string   `json:"vlan_mac_address,omitempty"`
+}
+
+type OpenStackNetwork struct {
+	ID        string             `json:"id"`
+	Type      string             `json:"type"`
+	Link      string             `json:"link"`
+	IPAddress string             `json:"ip_address,omitempty"`
+	Netmask   string             `json:"netmask,omitempty"`
+	Routes    []OpenStackRoute   `json:"routes,omitempty"`
+	NetworkID string             `json:"network_id,omitempty"`
+	Services  []OpenStackService `json:"services,omitempty"`
+}
+
+type OpenStackRoute struct {
+	Network string `json:"network"`
+	Netmask string `json:"netmask"`
+	Gateway string `json:"gateway"`
+}
+
+type OpenStackService struct {
+	Type    string `json:"type"`
+	Address string `json:"address"`
+}
+
+type ConfigDrive struct {
+	UserData    Multipart
+	MetaData    OpenStackMetaData
+	NetworkData *OpenStackNetworkData
+	Versions    []string
+}
+
+func (c *ConfigDrive) Write(sink FileSink) error {
+	if err := c.validate(); err != nil {
+		logger.Println("failed to write config drive", "func", getFuncName(), "configDrive", c, "error", err)
+		return err
+	}
+
+	var userData []byte
+	if c.UserData != nil {
+		b, err := renderBytes(c.UserData)
+		if err != nil {
+			logger.Println("failed to write config drive", "func", getFuncName(), "configDrive", c, "error", err)
+			return err
+		}
+
+		userData = b
+	}
+
+	metaData, err := json.Marshal(&c.MetaData)
+	if err != nil {
+		err = &Error{Op: "write", Err: err}
+		logger.Println("failed to write config drive", "func", getFuncName(), "configDrive", c, "error", err)
+		return err
+	}
+
+	var networkData []byte
+	if c.NetworkData != nil {
+		b, err := json.Marshal(c.NetworkData)
+		if err != nil {
+			err = &Error{Op: "write", Err: err}
+			logger.Println("failed to write config drive", "func", getFuncName(), "configDrive", c, "error", err)
+			return err
+		}
+
+		networkData = b
+	}
+
+	versions := c.Versions
+	if versions == nil {
+		versions = configDriveVersions
+	}
+
+	for _, version := range append([]string{configDriveLatest}, versions...) {
+		dir := path.Join(configDriveRoot, version)
+
+		if err := sink.WriteFile(path.Join(dir, configDriveMetaData), metaData, configDriveFileMode); err != nil {
+			logger.Println("failed to write config drive", "func", getFuncName(), "configDrive", c, "error", err)
+			return err
+		}
+
+		if userData != nil {
+			if err := sink.WriteFile(path.Join(dir, configDriveUserData), userData, configDriveFileMode); err != nil {
+				logger.Println("failed to write config drive", "func", getFuncName(), "configDrive", c, "error", err)
+				return err
+			}
+		}
+
+		if networkData != nil && (version == configDriveLatest || version >= configDriveNetworkDataSince) {
+			if err := sink.WriteFile(path.Join(dir, configDriveNetworkData), networkData, configDriveFileMode); err != nil {
+				logger.Println("failed to write config drive", "func", getFuncName(), "configDrive", c, "error", err)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *ConfigDrive) WriteDir(dir string) error {
+	return c.Write(NewDirSink(dir))
+}
+
+func (c *ConfigDrive) WriteISO9660(w io.Writer) error {
+	img, err := NewISO9660Image(configDriveVolumeID)
+	if err != nil {
+		logger.Println("failed to write config drive", "func", getFuncName(), "configDrive", c, "error", err)
+		return err
+	}
+
+	if err := c.Write(img); err != nil {
+		logger.Println("failed to write config drive", "func", getFuncName(), "configDrive", c, "error", err)
+		return err
+	}
+
+	if err := img.Render(w); err != nil {
+		logger.Println("failed to write config drive", "func", getFuncName(), "configDrive", c, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *ConfigDrive) validate() error {
+	if c.MetaData.UUID == "" {
+		return &Error{Op: "validate", Err: ErrMissingInstanceID}
+	}
+
+	for _, version := range c.Versions {
+		if !configDriveVersionRe.MatchString(version) {
+			return &Error{Op: "validate", Err: ErrInvalidVersion}
+		}
+	}
+
+	if c.NetworkData == nil {
+		return nil
+	}
+
+	links := make([]string, 0, len(c.NetworkData.Links))
+	for _, link := range c.NetworkData.Links {
+		for _, ref := range link.BondLinks {
+			if !slices.Contains(links, ref) {
+				return &Error{Op: "validate", Err: ErrInvalidReference}
+			}
+		}
+
+		if link.VLANLink != "" && !slices.Contains(links, link.VLANLink) {
+			return &Error{Op: "validate", Err: ErrInvalidReference}
+		}
+
+		links = append(links, link.ID)
+	}
+
+	for _, network := range c.NetworkData.Networks {
+		if !slices.Contains(links, network.Link) {
+			return &Error{Op: "validate", Err: ErrInvalidReference}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigDrive_Write(t *testing.T) {
+	userData := "Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+		"Mime-Version: 1.0\r\n" +
+		"\r\n" +
+		"--+Go+User+Data+Boundary==\r\n" +
+		"Content-Transfer-Encoding: 7bit\r\n" +
+		"Content-Type: text/cloud-config; charset=us-ascii\r\n" +
+		"\r\n" +
+		"#cloud-config\n" +
+		"timezone: Europe/London\r\n" +
+		"\r\n" +
+		"--+Go+User+Data+Boundary==--\r\n"
+
+	networkData := &OpenStackNetworkData{
+		Links: []OpenStackLink{
+			{ID: "eth0", Type: "phy", EthernetMACAddress: "52:54:00:12:34:00"},
+			{ID: "eth1", Type: "phy", EthernetMACAddress: "52:54:00:12:34:01"},
+			{ID: "bond0", Type: "bond", BondLinks: []string{"eth0", "eth1"}, BondMode: "802.3ad"},
+			{ID: "vlan100", Type: "vlan", VLANLink: "bond0", VLANID: 100},
+		},
+		Networks: []OpenStackNetwork{
+			{ID: "private", Type: "ipv4", Link: "vlan100", IPAddress: "10.0.0.10", Netmask: "255.255.255.0"},
+		},
+		Services: []OpenStackService{
+			{Type: "dns", Address: "10.0.0.2"},
+		},
+	}
+
+	type expected struct {
+		res mapSink
+		err error
+	}
+
+	tests := []struct {
+		name        string
+		configDrive ConfigDrive
+		expected    expected
+	}{
+		{
+			name: "positive case: latest only",
+			configDrive: ConfigDrive{
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London")))
+					return m
+				}(),
+				MetaData: OpenStackMetaData{
+					UUID:       "83679162-1378-4288-a2d4-70e13ec132aa",
+					Hostname:   "test.novalocal",
+					PublicKeys: map[string]string{"mykey": "ssh-ed25519 AAAA"},
+				},
+				Versions: []string{},
+			},
+			expected: expected{
+				res: mapSink{
+					"openstack/latest/meta_data.json": []byte(`{"uuid":"83679162-1378-4288-a2d4-70e13ec132aa","hostname":"test.novalocal","launch_index":0,"public_keys":{"mykey":"ssh-ed25519 AAAA"}}`),
+					"openstack/latest/user_data":      []byte(userData),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: versioned with network data",
+			configDrive: ConfigDrive{
+				MetaData:    OpenStackMetaData{UUID: "83679162-1378-4288-a2d4-70e13ec132aa"},
+				NetworkData: networkData,
+				Versions:    []string{"2013-10-17", "2015-10-15"},
+			},
+			expected: expected{
+				res: mapSink{
+					"openstack/latest/meta_data.json":        []byte(`{"uuid":"83679162-1378-4288-a2d4-70e13ec132aa","launch_index":0}`),
+					"openstack/latest/network_data.json":     []byte(`{"links":[{"id":"eth0","type":"phy","ethernet_mac_address":"52:54:00:12:34:00"},{"id":"eth1","type":"phy","ethernet_mac_address":"52:54:00:12:34:01"},{"id":"bond0","type":"bond","bond_links":["eth0","eth1"],"bond_mode":"802.3ad"},{"id":"vlan100","type":"vlan","vlan_link":"bond0","vlan_id":100}],"networks":[{"id":"private","type":"ipv4","link":"vlan100","ip_address":"10.0.0.10","netmask":"255.255.255.0"}],"services":[{"type":"dns","address":"10.0.0.2"}]}`),
+					"openstack/2013-10-17/meta_data.json":    []byte(`{"uuid":"83679162-1378-4288-a2d4-70e13ec132aa","launch_index":0}`),
+					"openstack/2015-10-15/meta_data.json":    []byte(`{"uuid":"83679162-1378-4288-a2d4-70e13ec132aa","launch_index":0}`),
+					"openstack/2015-10-15/network_data.json": []byte(`{"links":[{"id":"eth0","type":"phy","ethernet_mac_address":"52:54:00:12:34:00"},{"id":"eth1","type":"phy","ethernet_mac_address":"52:54:00:12:34:01"},{"id":"bond0","type":"bond","bond_links":["eth0","eth1"],"bond_mode":"802.3ad"},{"id":"vlan100","type":"vlan","vlan_link":"bond0","vlan_id":100}],"networks":[{"id":"private","type":"ipv4","link":"vlan100","ip_address":"10.0.0.10","netmask":"255.255.255.0"}],"services":[{"type":"dns","address":"10.0.0.2"}]}`),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: network data without services",
+			configDrive: ConfigDrive{
+				MetaData: OpenStackMetaData{UUID: "83679162-1378-4288-a2d4-70e13ec132aa"},
+				NetworkData: &OpenStackNetworkData{
+					Links:    []OpenStackLink{{ID: "eth0", Type: "phy"}},
+					Networks: []OpenStackNetwork{{ID: "private", Type: "ipv4_dhcp", Link: "eth0"}},
+				},
+				Versions: []string{},
+			},
+			expected: expected{
+				res: mapSink{
+					"openstack/latest/meta_data.json":    []byte(`{"uuid":"83679162-1378-4288-a2d4-70e13ec132aa","launch_index":0}`),
+					"openstack/latest/network_data.json": []byte(`{"links":[{"id":"eth0","type":"phy"}],"networks":[{"id":"private","type":"ipv4_dhcp","link":"eth0"}]}`),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: missing uuid",
+			configDrive: ConfigDrive{
+				MetaData: OpenStackMetaData{Hostname: "test.novalocal"},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrMissingInstanceID},
+			},
+		},
+		{
+			name: "negative case: invalid version",
+			configDrive: ConfigDrive{
+				MetaData: OpenStackMetaData{UUID: "83679162-1378-4288-a2d4-70e13ec132aa"},
+				Versions: []string{"folsom"},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidVersion},
+			},
+		},
+		{
+			name: "negative case: unknown link",
+			configDrive: ConfigDrive{
+				MetaData: OpenStackMetaData{UUID: "83679162-1378-4288-a2d4-70e13ec132aa"},
+				NetworkData: &OpenStackNetworkData{
+					Links:    []OpenStackLink{{ID: "eth0", Type: "phy"}},
+					Networks: []OpenStackNetwork{{ID: "private", Type: "ipv4_dhcp", Link: "eth1"}},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidReference},
+			},
+		},
+		{
+			name: "negative case: unknown bond link",
+			configDrive: ConfigDrive{
+				MetaData: OpenStackMetaData{UUID: "83679162-1378-4288-a2d4-70e13ec132aa"},
+				NetworkData: &OpenStackNetworkData{
+					Links: []OpenStackLink{
+						{ID: "eth0", Type: "phy"},
+						{ID: "bond0", Type: "bond", BondLinks: []string{"eth0", "eth1"}},
+					},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidReference},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := make(mapSink)
+			err := tt.configDrive.Write(sink)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, sink)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestConfigDrive_WriteISO9660(t *testing.T) {
+	configDrive := ConfigDrive{
+		MetaData: OpenStackMetaData{UUID: "83679162-1378-4288-a2d4-70e13ec132aa"},
+	}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, configDrive.WriteISO9660(buf))
+
+	content := readISO9660(buf.Bytes(), false)
+	assert.Equal(t, "config-2", content.volumeID)
+	assert.Equal(t, []byte(`{"uuid":"83679162-1378-4288-a2d4-70e13ec132aa","launch_index":0}`), content.files["openstack/latest/meta_data.json"])
+	assert.Equal(t, []byte(`{"uuid":"83679162-1378-4288-a2d4-70e13ec132aa","launch_index":0}`), content.files["openstack/2012-08-10/meta_data.json"])
+	assert.Len(t, content.files, 5)
+}
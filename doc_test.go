@@ -37,11 +37,19 @@ func ExampleMultipart_Render() {
 
 	cfg := []byte(`#cloud-config
 timezone: Europe/London`)
-	m.Append(userdata.NewPart(userdata.MediaTypeCloudConfig, cfg))
+	cfgPart, err := userdata.NewPart(userdata.MediaTypeCloudConfig, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	m.Append(cfgPart)
 
 	scr := []byte(`#!/bin/bash
 echo 'Hello World'`)
-	m.Append(userdata.NewPart(userdata.MediaTypeXShellscript, scr))
+	scrPart, err := userdata.NewPart(userdata.MediaTypeXShellscript, scr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	m.Append(scrPart)
 
 	buf := new(bytes.Buffer)
 	if err := m.Render(buf); err != nil {
@@ -80,11 +88,19 @@ func ExampleMultipart_Render_includesUtf8() {
 
 	cfg := []byte(`#cloud-config
 timezone: Asia/Tokyo`)
-	m.Append(userdata.NewPart(userdata.MediaTypeCloudConfig, cfg))
+	cfgPart, err := userdata.NewPart(userdata.MediaTypeCloudConfig, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	m.Append(cfgPart)
 
 	scr := []byte(`#!/bin/bash
 echo 'こんにちは世界'`)
-	m.Append(userdata.NewPart(userdata.MediaTypeXShellscript, scr))
+	scrPart, err := userdata.NewPart(userdata.MediaTypeXShellscript, scr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	m.Append(scrPart)
 
 	buf := new(bytes.Buffer)
 	if err := m.Render(buf); err != nil {
@@ -122,7 +138,11 @@ func ExampleMultipart_Render_withBoundary() {
 
 	scr := []byte(`#!/bin/bash
 echo 'Hello World'`)
-	m.Append(userdata.NewPart(userdata.MediaTypeXShellscript, scr))
+	scrPart, err := userdata.NewPart(userdata.MediaTypeXShellscript, scr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	m.Append(scrPart)
 
 	buf := new(bytes.Buffer)
 	if err := m.Render(buf); err != nil {
@@ -0,0 +1,181 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+const (
+	// limit applies to the raw payload, before base64 encoding
+	ec2MaxUserDataSize = 16 * 1024
+)
+
+type EC2UserData struct {
+	UserData    Multipart
+	DisableGzip bool
+}
+
+func (e *EC2UserData) Encode() (string, error) {
+	if e.UserData == nil {
+		return "", nil
+	}
+
+	data, err := renderBytes(e.UserData)
+	if err != nil {
+		logger.Println("failed to encode ec2 user data", "func", getFuncName(), "error", err)
+		return "", err
+	}
+
+	if !e.DisableGzip {
+		compressed, err := gzipBytes(data)
+		if err != nil {
+			logger.Println("failed to encode ec2 user data", "func", getFuncName(), "error", err)
+			return "", err
+		}
+
+		if len(compressed) < len(data) {
+			data = compressed
+		}
+	}
+
+	if len(data) > ec2MaxUserDataSize {
+		err := &Error{Op: "encode", Err: ErrPayloadTooLarge}
+		logger.Println("failed to encode ec2 user data", "func", getFuncName(), "size", len(data), "error", err)
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+func (e *EC2UserData) LaunchTemplateData() ([]byte, error) {
+	userData, err := e.Encode()
+	if err != nil {
+		logger.Println("failed to build launch template data", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	doc := ec2LaunchTemplateVersion{LaunchTemplateData: ec2UserDataField{UserData: userData}}
+
+	b, err := json.Marshal(&doc)
+	if err != nil {
+		err = &Error{Op: "encode", Err: err}
+		logger.Println("failed to build launch template data", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (e *EC2UserData) RunInstances() ([]byte, error) {
+	userData, err := e.Encode()
+	if err != nil {
+		logger.Println("failed to build run instances input", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	b, err := json.Marshal(&ec2UserDataField{UserData: userData})
+	if err != nil {
+		err = &Error{Op: "encode", Err: err}
+		logger.Println("failed to build run instances input", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func DecodeEC2UserData(s string) (Multipart, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		err := &Error{Op: "decode", Err: ErrInvalidBody}
+		logger.Println("failed to decode ec2 user data", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	m, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		logger.Println("failed to decode ec2 user data", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func ParseEC2LaunchTemplateVersions(r io.Reader) (map[int64]Multipart, error) {
+	var doc struct {
+		LaunchTemplateVersions []ec2LaunchTemplateVersion `json:"LaunchTemplateVersions"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		err = &Error{Op: "parse", Err: err}
+		logger.Println("failed to parse launch template versions", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	versions := make(map[int64]Multipart, len(doc.LaunchTemplateVersions))
+	for _, v := range doc.LaunchTemplateVersions {
+		if v.LaunchTemplateData.UserData == "" {
+			continue
+		}
+
+		m, err := DecodeEC2UserData(v.LaunchTemplateData.UserData)
+		if err != nil {
+			logger.Println("failed to parse launch template versions", "func", getFuncName(), "version", v.VersionNumber, "error", err)
+			return nil, err
+		}
+
+		versions[v.VersionNumber] = m
+	}
+
+	return versions, nil
+}
+
+type ec2LaunchTemplateVersion struct {
+	VersionNumber      int64            `json:"VersionNumber,omitempty"`
+	LaunchTemplateData ec2UserDataField `json:"LaunchTemplateData"`
+}
+
+type ec2UserDataField struct {
+	UserData string `json:"UserData"`
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	zw, err := gzip.NewWriterLevel(buf, gzip.BestCompression)
+	if err != nil {
+		return nil, &Error{Op: "encode", Err: err}
+	}
+
+	if _, err := zw.Write(data); err != nil {
+		return nil, &Error{Op: "encode", Err: err}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, &Error{Op: "encode", Err: err}
+	}
+
+	return buf.Bytes(), nil
+}
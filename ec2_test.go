@@ -0,0 +1,196 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEC2UserData_Encode(t *testing.T) {
+	padded := func() Multipart {
+		m, _ := NewMultipart()
+		m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+strings.Repeat("# padding\n", 50))))
+		return m
+	}
+
+	random := func(n int) Multipart {
+		b := make([]byte, n)
+		_, _ = rand.Read(b)
+
+		m, _ := NewMultipart()
+		m.Append(mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\n"+"# "+base64.StdEncoding.EncodeToString(b))))
+		return m
+	}
+
+	type expected struct {
+		gzip bool
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		userData EC2UserData
+		expected expected
+	}{
+		{
+			name: "positive case: gzip",
+			userData: EC2UserData{
+				UserData: padded(),
+			},
+			expected: expected{
+				gzip: true,
+			},
+		},
+		{
+			name: "positive case: gzip disabled",
+			userData: EC2UserData{
+				UserData:    padded(),
+				DisableGzip: true,
+			},
+			expected: expected{
+				gzip: false,
+			},
+		},
+		{
+			name: "negative case: too large",
+			userData: EC2UserData{
+				UserData: random(16 * 1024),
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrPayloadTooLarge},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.userData.Encode()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+
+				data, err := base64.StdEncoding.DecodeString(actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.gzip, strings.HasPrefix(string(data), "\x1f\x8b"))
+
+				m, err := DecodeEC2UserData(actual)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.userData.UserData.Parts(), m.Parts())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestEC2UserData_LaunchTemplateData(t *testing.T) {
+	m, _ := NewMultipart()
+	e := &EC2UserData{UserData: m, DisableGzip: true}
+
+	actual, err := e.LaunchTemplateData()
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"LaunchTemplateData":{"UserData":"Q29udGVudC1UeXBlOiBtdWx0aXBhcnQvbWl4ZWQ7IGJvdW5kYXJ5PSIrR28rVXNlcitEYXRhK0JvdW5kYXJ5PT0iDQpNaW1lLVZlcnNpb246IDEuMA0KDQotLStHbytVc2VyK0RhdGErQm91bmRhcnk9PS0tDQo="}}`, string(actual))
+}
+
+func TestEC2UserData_RunInstances(t *testing.T) {
+	m, _ := NewMultipart()
+	e := &EC2UserData{UserData: m, DisableGzip: true}
+
+	actual, err := e.RunInstances()
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"UserData":"Q29udGVudC1UeXBlOiBtdWx0aXBhcnQvbWl4ZWQ7IGJvdW5kYXJ5PSIrR28rVXNlcitEYXRhK0JvdW5kYXJ5PT0iDQpNaW1lLVZlcnNpb246IDEuMA0KDQotLStHbytVc2VyK0RhdGErQm91bmRhcnk9PS0tDQo="}`, string(actual))
+}
+
+func TestParseEC2LaunchTemplateVersions(t *testing.T) {
+	type expected struct {
+		res map[int64][]Part
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected expected
+	}{
+		{
+			name: "positive case",
+			input: `{
+    "LaunchTemplateVersions": [
+        {
+            "LaunchTemplateId": "lt-0123456789abcdef0",
+            "VersionNumber": 2,
+            "LaunchTemplateData": {
+                "ImageId": "ami-0123456789abcdef0",
+                "UserData": "IyEvYmluL3NoCmVjaG8gaGVsbG8K"
+            }
+        },
+        {
+            "LaunchTemplateId": "lt-0123456789abcdef0",
+            "VersionNumber": 1,
+            "LaunchTemplateData": {
+                "ImageId": "ami-0123456789abcdef0"
+            }
+        }
+    ]
+}`,
+			expected: expected{
+				res: map[int64][]Part{
+					// base64.StdEncoding.EncodeToString([]byte("#!/bin/sh\necho hello\n"))
+					2: {mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\n"+"echo hello\n"))},
+				},
+			},
+		},
+		{
+			name:  "negative case: invalid base64",
+			input: `{"LaunchTemplateVersions": [{"VersionNumber": 1, "LaunchTemplateData": {"UserData": "!!!"}}]}`,
+			expected: expected{
+				err: &Error{Op: "decode", Err: ErrInvalidBody},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseEC2LaunchTemplateVersions(strings.NewReader(tt.input))
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+
+				parts := make(map[int64][]Part, len(actual))
+				for version, m := range actual {
+					parts[version] = m.Parts()
+				}
+				assert.Equal(t, tt.expected.res, parts)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"encoding/base64"
+	"io"
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	nodeConfigAPIVersion = "node.eks.aws/v1alpha1"
+	nodeConfigKind       = "NodeConfig"
+)
+
+type NodeConfig struct {
+	Cluster      NodeConfigCluster    `yaml:"cluster,omitempty"`
+	Kubelet      NodeConfigKubelet    `yaml:"kubelet,omitempty"`
+	Containerd   NodeConfigContainerd `yaml:"containerd,omitempty"`
+	Instance     map[string]any       `yaml:"instance,omitempty"`
+	FeatureGates map[string]bool      `yaml:"featureGates,omitempty"`
+}
+
+type NodeConfigCluster struct {
+	Name                 string `yaml:"name,omitempty"`
+	APIServerEndpoint    string `yaml:"apiServerEndpoint,omitempty"`
+	CertificateAuthority string `yaml:"certificateAuthority,omitempty"`
+	CIDR                 string `yaml:"cidr,omitempty"`
+}
+
+type NodeConfigKubelet struct {
+	Config map[string]any `yaml:"config,omitempty"`
+	Flags  []string       `yaml:"flags,omitempty"`
+}
+
+type NodeConfigContainerd struct {
+	Config string `yaml:"config,omitempty"`
+}
+
+type nodeConfigDocument struct {
+	APIVersion string     `yaml:"apiVersion"`
+	Kind       string     `yaml:"kind"`
+	Spec       NodeConfig `yaml:"spec"`
+}
+
+func (c *NodeConfig) Render(w io.Writer) error {
+	if err := c.validate(); err != nil {
+		err = &Error{Op: "validate", Err: err}
+		logger.Println("failed to render node config", "func", getFuncName(), "nodeConfig", c, "error", err)
+		return err
+	}
+
+	doc := nodeConfigDocument{APIVersion: nodeConfigAPIVersion, Kind: nodeConfigKind, Spec: *c}
+	if err := renderYAML(w, &doc); err != nil {
+		logger.Println("failed to render node config", "func", getFuncName(), "nodeConfig", c, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *NodeConfig) Build() (Part, error) {
+	b, err := renderBytes(c)
+	if err != nil {
+		logger.Println("failed to build node config", "func", getFuncName(), "nodeConfig", c, "error", err)
+		return nil, err
+	}
+
+	p, err := NewPart(MediaTypeNodeEKSAWS, b)
+	if err != nil {
+		logger.Println("failed to build node config", "func", getFuncName(), "nodeConfig", c, "error", err)
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func ParseNodeConfig(body []byte) (*NodeConfig, error) {
+	c, err := parseNodeConfig(body)
+	if err != nil {
+		err = &Error{Op: "parse", Err: err}
+		logger.Println("failed to parse node config", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func ExtractNodeConfigs(m Multipart) ([]*NodeConfig, error) {
+	configs := make([]*NodeConfig, 0)
+	for _, p := range m.Parts() {
+		if p.MediaType() != MediaTypeNodeEKSAWS {
+			continue
+		}
+
+		c, err := ParseNodeConfig(p.Body())
+		if err != nil {
+			logger.Println("failed to extract node config", "func", getFuncName(), "error", err)
+			return nil, err
+		}
+
+		configs = append(configs, c)
+	}
+
+	// nodeadm merges every document and only requires the cluster details once
+	if len(configs) > 0 {
+		if err := mergeNodeConfigs(configs).validateComplete(); err != nil {
+			err = &Error{Op: "validate", Err: err}
+			logger.Println("failed to extract node config", "func", getFuncName(), "error", err)
+			return nil, err
+		}
+	}
+
+	return configs, nil
+}
+
+func parseNodeConfig(body []byte) (*NodeConfig, error) {
+	var doc nodeConfigDocument
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.APIVersion != nodeConfigAPIVersion {
+		return nil, ErrInvalidVersion
+	}
+
+	if doc.Kind != nodeConfigKind {
+		return nil, ErrInvalidType
+	}
+
+	if err := doc.Spec.validate(); err != nil {
+		return nil, err
+	}
+
+	return &doc.Spec, nil
+}
+
+func mergeNodeConfigs(configs []*NodeConfig) *NodeConfig {
+	merged := &NodeConfig{}
+	for _, c := range configs {
+		mergeString(&merged.Cluster.Name, c.Cluster.Name)
+		mergeString(&merged.Cluster.APIServerEndpoint, c.Cluster.APIServerEndpoint)
+		mergeString(&merged.Cluster.CertificateAuthority, c.Cluster.CertificateAuthority)
+		mergeString(&merged.Cluster.CIDR, c.Cluster.CIDR)
+		mergeString(&merged.Containerd.Config, c.Containerd.Config)
+
+		merged.Kubelet.Flags = append(merged.Kubelet.Flags, c.Kubelet.Flags...)
+		merged.Kubelet.Config = mergeMap(merged.Kubelet.Config, c.Kubelet.Config)
+		merged.Instance = mergeMap(merged.Instance, c.Instance)
+		merged.FeatureGates = mergeMap(merged.FeatureGates, c.FeatureGates)
+	}
+
+	return merged
+}
+
+func mergeString(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
+
+func mergeMap[V any](dst map[string]V, src map[string]V) map[string]V {
+	if len(src) == 0 {
+		return dst
+	}
+
+	if dst == nil {
+		dst = make(map[string]V, len(src))
+	}
+
+	for k, v := range src {
+		dst[k] = v
+	}
+
+	return dst
+}
+
+func (c *NodeConfig) validate() error {
+	if c.Cluster.APIServerEndpoint != "" {
+		u, err := url.Parse(c.Cluster.APIServerEndpoint)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return ErrInvalidURL
+		}
+	}
+
+	if c.Cluster.CertificateAuthority != "" {
+		if _, err := base64.StdEncoding.DecodeString(c.Cluster.CertificateAuthority); err != nil {
+			return ErrInvalidCertificate
+		}
+	}
+
+	if c.Cluster.CIDR != "" && !validCIDR(c.Cluster.CIDR) {
+		return ErrInvalidCIDR
+	}
+
+	return nil
+}
+
+func (c *NodeConfig) validateComplete() error {
+	if c.Cluster.Name == "" {
+		return ErrInvalidName
+	}
+
+	if c.Cluster.APIServerEndpoint == "" {
+		return ErrInvalidURL
+	}
+
+	if c.Cluster.CertificateAuthority == "" {
+		return ErrInvalidCertificate
+	}
+
+	if c.Cluster.CIDR == "" {
+		return ErrInvalidCIDR
+	}
+
+	return c.validate()
+}
@@ -0,0 +1,392 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeConfig_Render(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name       string
+		nodeConfig NodeConfig
+		expected   expected
+	}{
+		{
+			name: "positive case",
+			nodeConfig: NodeConfig{
+				Cluster: NodeConfigCluster{
+					Name:                 "my-cluster",
+					APIServerEndpoint:    "https://example.com",
+					CertificateAuthority: "Y2VydGlmaWNhdGVBdXRob3JpdHk=",
+					CIDR:                 "10.100.0.0/16",
+				},
+				Kubelet: NodeConfigKubelet{
+					Config: map[string]any{"maxPods": 17},
+					Flags:  []string{"--node-labels=role=worker"},
+				},
+				Containerd: NodeConfigContainerd{
+					Config: "[plugins.\"io.containerd.grpc.v1.cri\".containerd]\n" + "discard_unpacked_layers = false\n",
+				},
+			},
+			expected: expected{
+				res: "apiVersion: node.eks.aws/v1alpha1\n" +
+					"kind: NodeConfig\n" +
+					"spec:\n" +
+					"  cluster:\n" +
+					"    name: my-cluster\n" +
+					"    apiServerEndpoint: https://example.com\n" +
+					"    certificateAuthority: Y2VydGlmaWNhdGVBdXRob3JpdHk=\n" +
+					"    cidr: 10.100.0.0/16\n" +
+					"  kubelet:\n" +
+					"    config:\n" +
+					"      maxPods: 17\n" +
+					"    flags:\n" +
+					"      - --node-labels=role=worker\n" +
+					"  containerd:\n" +
+					"    config: |\n" +
+					"      [plugins.\"io.containerd.grpc.v1.cri\".containerd]\n" +
+					"      discard_unpacked_layers = false\n",
+			},
+		},
+		{
+			name: "positive case: partial override",
+			nodeConfig: NodeConfig{
+				Kubelet: NodeConfigKubelet{
+					Flags: []string{"--node-labels=role=gpu"},
+				},
+				FeatureGates: map[string]bool{"InstanceIdNodeName": true},
+			},
+			expected: expected{
+				res: "apiVersion: node.eks.aws/v1alpha1\n" +
+					"kind: NodeConfig\n" +
+					"spec:\n" +
+					"  kubelet:\n" +
+					"    flags:\n" +
+					"      - --node-labels=role=gpu\n" +
+					"  featureGates:\n" +
+					"    InstanceIdNodeName: true\n",
+			},
+		},
+		{
+			name: "negative case: plain http endpoint",
+			nodeConfig: NodeConfig{
+				Cluster: NodeConfigCluster{
+					Name:                 "my-cluster",
+					APIServerEndpoint:    "http://example.com",
+					CertificateAuthority: "Y2VydGlmaWNhdGVBdXRob3JpdHk=",
+					CIDR:                 "10.100.0.0/16",
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidURL},
+			},
+		},
+		{
+			name: "negative case: invalid certificate authority",
+			nodeConfig: NodeConfig{
+				Cluster: NodeConfigCluster{
+					Name:                 "my-cluster",
+					APIServerEndpoint:    "https://example.com",
+					CertificateAuthority: "-----BEGIN CERTIFICATE-----",
+					CIDR:                 "10.100.0.0/16",
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidCertificate},
+			},
+		},
+		{
+			name: "negative case: invalid cidr",
+			nodeConfig: NodeConfig{
+				Cluster: NodeConfigCluster{
+					Name:                 "my-cluster",
+					APIServerEndpoint:    "https://example.com",
+					CertificateAuthority: "Y2VydGlmaWNhdGVBdXRob3JpdHk=",
+					CIDR:                 "10.100.0.0",
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidCIDR},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.nodeConfig.Render(buf)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, buf.String())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestNodeConfig_Build(t *testing.T) {
+	c := &NodeConfig{
+		Cluster: NodeConfigCluster{
+			Name:                 "my-cluster",
+			APIServerEndpoint:    "https://example.com",
+			CertificateAuthority: "Y2VydGlmaWNhdGVBdXRob3JpdHk=",
+			CIDR:                 "10.100.0.0/16",
+		},
+	}
+
+	actual, err := c.Build()
+
+	assert.NoError(t, err)
+	assert.Equal(t, MediaTypeNodeEKSAWS, actual.MediaType())
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, actual.Render(buf))
+	assert.True(t, strings.HasPrefix(buf.String(), "Content-Transfer-Encoding: 7bit\r\n"+
+		"Content-Type: application/node.eks.aws; charset=us-ascii\r\n"))
+}
+
+func TestParseNodeConfig(t *testing.T) {
+	type expected struct {
+		res *NodeConfig
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected expected
+	}{
+		{
+			name: "positive case",
+			body: "---\n" +
+				"apiVersion: node.eks.aws/v1alpha1\n" +
+				"kind: NodeConfig\n" +
+				"spec:\n" +
+				"  cluster:\n" +
+				"    name: my-cluster\n" +
+				"    apiServerEndpoint: https://example.com\n" +
+				"    certificateAuthority: Y2VydGlmaWNhdGVBdXRob3JpdHk=\n" +
+				"    cidr: 10.100.0.0/16\n" +
+				"  kubelet:\n" +
+				"    flags:\n" +
+				"      - --node-labels=role=worker\n",
+			expected: expected{
+				res: &NodeConfig{
+					Cluster: NodeConfigCluster{
+						Name:                 "my-cluster",
+						APIServerEndpoint:    "https://example.com",
+						CertificateAuthority: "Y2VydGlmaWNhdGVBdXRob3JpdHk=",
+						CIDR:                 "10.100.0.0/16",
+					},
+					Kubelet: NodeConfigKubelet{
+						Flags: []string{"--node-labels=role=worker"},
+					},
+				},
+			},
+		},
+		{
+			name: "positive case: partial override",
+			body: "---\n" +
+				"apiVersion: node.eks.aws/v1alpha1\n" +
+				"kind: NodeConfig\n" +
+				"spec:\n" +
+				"  featureGates:\n" +
+				"    InstanceIdNodeName: true\n" +
+				"  instance:\n" +
+				"    localStorage:\n" +
+				"      strategy: RAID0\n" +
+				"  kubelet:\n" +
+				"    config:\n" +
+				"      maxPods: 110\n",
+			expected: expected{
+				res: &NodeConfig{
+					Kubelet: NodeConfigKubelet{
+						Config: map[string]any{"maxPods": 110},
+					},
+					Instance: map[string]any{
+						"localStorage": map[string]any{"strategy": "RAID0"},
+					},
+					FeatureGates: map[string]bool{"InstanceIdNodeName": true},
+				},
+			},
+		},
+		{
+			name: "negative case: invalid cidr",
+			body: "apiVersion: node.eks.aws/v1alpha1\n" +
+				"kind: NodeConfig\n" +
+				"spec:\n" +
+				"  cluster:\n" +
+				"    cidr: 10.100.0.0\n",
+			expected: expected{
+				err: &Error{Op: "parse", Err: ErrInvalidCIDR},
+			},
+		},
+		{
+			name: "negative case: unknown api version",
+			body: "apiVersion: node.eks.aws/v1\n" +
+				"kind: NodeConfig\n",
+			expected: expected{
+				err: &Error{Op: "parse", Err: ErrInvalidVersion},
+			},
+		},
+		{
+			name: "negative case: unknown kind",
+			body: "apiVersion: node.eks.aws/v1alpha1\n" +
+				"kind: NodeGroup\n",
+			expected: expected{
+				err: &Error{Op: "parse", Err: ErrInvalidType},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseNodeConfig([]byte(tt.body))
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestExtractNodeConfigs(t *testing.T) {
+	const (
+		cluster = "---\n" +
+			"apiVersion: node.eks.aws/v1alpha1\n" +
+			"kind: NodeConfig\n" +
+			"spec:\n" +
+			"  cluster:\n" +
+			"    name: my-cluster\n" +
+			"    apiServerEndpoint: https://example.com\n" +
+			"    certificateAuthority: Y2VydGlmaWNhdGVBdXRob3JpdHk=\n" +
+			"    cidr: 10.100.0.0/16\n"
+		override = "---\n" +
+			"apiVersion: node.eks.aws/v1alpha1\n" +
+			"kind: NodeConfig\n" +
+			"spec:\n" +
+			"  kubelet:\n" +
+			"    flags:\n" +
+			"      - --node-labels=role=worker\n"
+	)
+
+	userData := func(docs ...string) string {
+		data := "Content-Type: multipart/mixed; boundary=\"//\"\r\n" +
+			"MIME-Version: 1.0\r\n" +
+			"\r\n"
+		for _, doc := range docs {
+			data += "--//\r\n" +
+				"Content-Type: application/node.eks.aws\r\n" +
+				"\r\n" +
+				doc +
+				"\r\n"
+		}
+		data += "--//\r\n" +
+			"Content-Type: text/x-shellscript; charset=\"us-ascii\"\r\n" +
+			"\r\n" +
+			"#!/bin/bash\n" +
+			"echo \"Hello, World!\"\n" +
+			"\r\n" +
+			"--//--\r\n"
+		return data
+	}
+
+	type expected struct {
+		res []*NodeConfig
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		expected expected
+	}{
+		{
+			name: "positive case: merged documents",
+			data: userData(cluster, override),
+			expected: expected{
+				res: []*NodeConfig{
+					{
+						Cluster: NodeConfigCluster{
+							Name:                 "my-cluster",
+							APIServerEndpoint:    "https://example.com",
+							CertificateAuthority: "Y2VydGlmaWNhdGVBdXRob3JpdHk=",
+							CIDR:                 "10.100.0.0/16",
+						},
+					},
+					{
+						Kubelet: NodeConfigKubelet{
+							Flags: []string{"--node-labels=role=worker"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "positive case: no documents",
+			data: userData(),
+			expected: expected{
+				res: []*NodeConfig{},
+			},
+		},
+		{
+			name: "negative case: missing cluster",
+			data: userData(override),
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidName},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Parse(strings.NewReader(tt.data))
+			assert.NoError(t, err)
+
+			actual, err := ExtractNodeConfigs(m)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -26,7 +26,45 @@ import (
 )
 
 var (
-	ErrInvalidBoundary = errors.New("invalid boundary")
+	ErrInvalidBoundary       = errors.New("invalid boundary")
+	ErrInvalidMediaType      = errors.New("invalid media type")
+	ErrUnknownMediaType      = errors.New("unknown media type")
+	ErrUnsupportedMediaType  = errors.New("unsupported media type")
+	ErrReservedMediaType     = errors.New("reserved media type")
+	ErrDuplicateMediaType    = errors.New("duplicate media type")
+	ErrInvalidHandlerVersion = errors.New("invalid handler version")
+	ErrInvalidFrequency      = errors.New("invalid frequency")
+	ErrInvalidEncoding       = errors.New("invalid encoding")
+	ErrMissingShebang        = errors.New("missing shebang")
+	ErrInvalidShebang        = errors.New("invalid shebang")
+	ErrInvalidEnvName        = errors.New("invalid environment variable name")
+	ErrInvalidEnvValue       = errors.New("invalid environment variable value")
+	ErrUnsupportedEnv        = errors.New("environment variables unsupported by interpreter")
+	ErrCRLFLineEndings       = errors.New("crlf line endings")
+	ErrInvalidName           = errors.New("invalid name")
+	ErrInvalidBody           = errors.New("invalid body")
+	ErrMarkerMismatch        = errors.New("marker mismatch")
+	ErrMissingInstanceID     = errors.New("missing instance id")
+	ErrInvalidPath           = errors.New("invalid path")
+	ErrInvalidVolumeID       = errors.New("invalid volume id")
+	ErrTooManyFiles          = errors.New("too many files")
+	ErrPayloadTooLarge       = errors.New("payload too large")
+	ErrInvalidVersion        = errors.New("invalid version")
+	ErrInvalidReference      = errors.New("invalid reference")
+	ErrInvalidURL            = errors.New("invalid url")
+	ErrInvalidMACAddress     = errors.New("invalid mac address")
+	ErrInvalidIPAddress      = errors.New("invalid ip address")
+	ErrInvalidCIDR           = errors.New("invalid cidr")
+	ErrInvalidVLANID         = errors.New("invalid vlan id")
+	ErrInvalidType           = errors.New("invalid type")
+	ErrInvalidCertificate    = errors.New("invalid certificate")
+	ErrInvalidConfiguration  = errors.New("invalid configuration")
+	ErrUnknownSetting        = errors.New("unknown setting")
+	ErrInvalidTaint          = errors.New("invalid taint")
+	ErrMissingSource         = errors.New("missing source")
+	ErrMissingMarker         = errors.New("missing marker")
+	ErrMissingTaskName       = errors.New("missing task name")
+	ErrInvalidRunAs          = errors.New("invalid run as")
 )
 
 type Error struct {
@@ -0,0 +1,524 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	fatSectorSize     = 512
+	fatReservedCount  = 1
+	fatCount          = 2
+	fatRootEntryCount = 512
+	fatEntrySize      = 32
+	fatMinClusters    = 2048
+	fatMaxClusters12  = 4084
+	fatMaxClusters16  = 65524
+	fatMaxLabel       = 11
+	fatMaxLongName    = 255
+
+	fatAttrVolumeID  = 0x08
+	fatAttrDirectory = 0x10
+	fatAttrArchive   = 0x20
+	fatAttrLongName  = 0x0f
+
+	// 1980-01-01, the FAT epoch, keeps images reproducible
+	fatDate = 0x0021
+)
+
+var (
+	fatShortNameSpecials = "!#$%&'()-@^_`{}~"
+	fatLabelInvalid      = "\"*+,./:;<=>?[\\]|"
+)
+
+type FATImage interface {
+	FileSink
+	Renderer
+}
+
+type fatImage struct {
+	label string
+	root  *fileNode
+}
+
+type fatNode struct {
+	*fileNode
+	children  []*fatNode
+	shortName [11]byte
+	longName  bool
+	cluster   uint32
+	clusters  uint32
+}
+
+type fatLayout struct {
+	bits              int
+	sectorsPerCluster uint32
+	clusters          uint32
+	fatSectors        uint32
+	totalSectors      uint32
+}
+
+func NewFATImage(label string) (FATImage, error) {
+	if label == "" || len(label) > fatMaxLabel || strings.IndexFunc(label, isNotPrintableASCII) >= 0 || strings.ContainsAny(label, fatLabelInvalid) {
+		err := &Error{Op: "initialize", Err: ErrInvalidVolumeID}
+		logger.Println("failed to initialize fat image", "func", getFuncName(), "label", label, "error", err)
+		return nil, err
+	}
+
+	return &fatImage{label: label, root: newFileTree()}, nil
+}
+
+func (img *fatImage) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	for _, elem := range strings.Split(name, "/") {
+		if len(utf16.Encode([]rune(elem))) > fatMaxLongName {
+			err := &Error{Op: "write", Err: ErrInvalidPath}
+			logger.Println("failed to write fat file", "func", getFuncName(), "name", name, "error", err)
+			return err
+		}
+	}
+
+	if err := img.root.writeFile(name, data, perm); err != nil {
+		err = &Error{Op: "write", Err: err}
+		logger.Println("failed to write fat file", "func", getFuncName(), "name", name, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (img *fatImage) Render(w io.Writer) error {
+	b, err := img.build()
+	if err != nil {
+		logger.Println("failed to render fat image", "func", getFuncName(), "label", img.label, "error", err)
+		return err
+	}
+
+	if _, err := w.Write(b); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render fat image", "func", getFuncName(), "label", img.label, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (img *fatImage) build() ([]byte, error) {
+	root := newFATNode(img.root)
+
+	layout, err := planFAT(root)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterSize := int(layout.sectorsPerCluster) * fatSectorSize
+	rootSectors := uint32(fatRootEntryCount * fatEntrySize / fatSectorSize)
+	fatStart := uint32(fatReservedCount)
+	rootStart := fatStart + fatCount*layout.fatSectors
+	dataStart := rootStart + rootSectors
+
+	out := make([]byte, int(layout.totalSectors)*fatSectorSize)
+	copy(out, img.bootSector(layout))
+
+	fat := make([]uint32, layout.clusters+2)
+	fat[0] = 0xfffff00 | 0xf8
+	fat[1] = 0xfffffff
+
+	next := uint32(2)
+	var allocate func(dir *fatNode)
+	allocate = func(dir *fatNode) {
+		for _, child := range dir.children {
+			if child.clusters == 0 {
+				continue
+			}
+
+			child.cluster = next
+			for i := uint32(0); i < child.clusters; i++ {
+				fat[next+i] = next + i + 1
+			}
+			fat[next+child.clusters-1] = 0xfffffff
+			next += child.clusters
+		}
+
+		for _, child := range dir.children {
+			if child.mode.IsDir() {
+				allocate(child)
+			}
+		}
+	}
+	allocate(root)
+
+	table := encodeFAT(fat, layout)
+	for i := uint32(0); i < fatCount; i++ {
+		copy(out[int(fatStart+i*layout.fatSectors)*fatSectorSize:], table)
+	}
+
+	rootEntries := [][]byte{fatLabelEntry(img.label)}
+	rootEntries = append(rootEntries, fatDirectoryEntries(root)...)
+	copy(out[int(rootStart)*fatSectorSize:], bytes.Join(rootEntries, nil))
+
+	clusterOffset := func(cluster uint32) int {
+		return int(dataStart)*fatSectorSize + int(cluster-2)*clusterSize
+	}
+
+	var write func(dir *fatNode, parent uint32)
+	write = func(dir *fatNode, parent uint32) {
+		for _, child := range dir.children {
+			if child.mode.IsDir() {
+				entries := [][]byte{
+					fatShortEntry([11]byte{'.', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' '}, fatAttrDirectory, child.cluster, 0),
+					fatShortEntry([11]byte{'.', '.', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' ', ' '}, fatAttrDirectory, parent, 0),
+				}
+				entries = append(entries, fatDirectoryEntries(child)...)
+				copy(out[clusterOffset(child.cluster):], bytes.Join(entries, nil))
+
+				write(child, child.cluster)
+				continue
+			}
+
+			if child.clusters > 0 {
+				copy(out[clusterOffset(child.cluster):], child.data)
+			}
+		}
+	}
+	write(root, 0)
+
+	return out, nil
+}
+
+func (img *fatImage) bootSector(layout fatLayout) []byte {
+	b := make([]byte, fatSectorSize)
+
+	copy(b[0:3], []byte{0xeb, 0x3c, 0x90})
+	copy(b[3:11], "GOUSERDA")
+	binary.LittleEndian.PutUint16(b[11:13], fatSectorSize)
+	b[13] = byte(layout.sectorsPerCluster)
+	binary.LittleEndian.PutUint16(b[14:16], fatReservedCount)
+	b[16] = fatCount
+	binary.LittleEndian.PutUint16(b[17:19], fatRootEntryCount)
+	if layout.totalSectors < 0x10000 {
+		binary.LittleEndian.PutUint16(b[19:21], uint16(layout.totalSectors))
+	} else {
+		binary.LittleEndian.PutUint32(b[32:36], layout.totalSectors)
+	}
+	b[21] = 0xf8
+	binary.LittleEndian.PutUint16(b[22:24], uint16(layout.fatSectors))
+	binary.LittleEndian.PutUint16(b[24:26], 32)
+	binary.LittleEndian.PutUint16(b[26:28], 64)
+
+	b[36] = 0x80
+	b[38] = 0x29
+	copy(b[39:43], img.serial())
+	copy(b[43:54], padded(img.label, fatMaxLabel))
+	copy(b[54:62], padded(fmt.Sprintf("FAT%d", layout.bits), 8))
+
+	b[510] = 0x55
+	b[511] = 0xaa
+
+	return b
+}
+
+func (img *fatImage) serial() []byte {
+	h := sha256.New()
+	h.Write([]byte(img.label))
+
+	var walk func(dir *fileNode, prefix string)
+	walk = func(dir *fileNode, prefix string) {
+		for _, child := range dir.sortedChildren() {
+			fmt.Fprintf(h, "%s%s\x00%o\x00%d\x00", prefix, child.name, child.mode, len(child.data))
+			h.Write(child.data)
+
+			if child.mode.IsDir() {
+				walk(child, prefix+child.name+"/")
+			}
+		}
+	}
+	walk(img.root, "")
+
+	return h.Sum(nil)[:4]
+}
+
+func newFATNode(n *fileNode) *fatNode {
+	node := &fatNode{fileNode: n}
+	children := n.sortedChildren()
+
+	// names that already are valid short names keep them, the rest get a numeric tail
+	used := make(map[[11]byte]bool)
+	exact := make(map[*fileNode][11]byte)
+	for _, c := range children {
+		if short, ok := fatExactShortName(c.name); ok && !used[short] {
+			exact[c] = short
+			used[short] = true
+		}
+	}
+
+	for _, c := range children {
+		child := newFATNode(c)
+		if short, ok := exact[c]; ok {
+			child.shortName = short
+		} else {
+			child.shortName = fatGeneratedShortName(c.name, used)
+			child.longName = true
+			used[child.shortName] = true
+		}
+
+		node.children = append(node.children, child)
+	}
+
+	return node
+}
+
+func planFAT(root *fatNode) (fatLayout, error) {
+	if len(fatDirectoryEntries(root))+1 > fatRootEntryCount {
+		return fatLayout{}, &Error{Op: "render", Err: ErrTooManyFiles}
+	}
+
+	for spc := uint32(1); spc <= 64; spc *= 2 {
+		clusterSize := spc * fatSectorSize
+
+		var needed uint32
+		var count func(dir *fatNode)
+		count = func(dir *fatNode) {
+			for _, child := range dir.children {
+				size := uint32(len(child.data))
+				if child.mode.IsDir() {
+					size = uint32(len(fatDirectoryEntries(child))+2) * fatEntrySize
+				}
+
+				child.clusters = (size + clusterSize - 1) / clusterSize
+				needed += child.clusters
+
+				if child.mode.IsDir() {
+					count(child)
+				}
+			}
+		}
+		count(root)
+
+		clusters := needed
+		if clusters < fatMinClusters {
+			clusters = fatMinClusters
+		}
+
+		if clusters > fatMaxClusters16 {
+			continue
+		}
+
+		bits := 12
+		if clusters > fatMaxClusters12 {
+			bits = 16
+		}
+
+		fatSectors := ((clusters+2)*uint32(bits)/8 + 1 + fatSectorSize - 1) / fatSectorSize
+		rootSectors := uint32(fatRootEntryCount * fatEntrySize / fatSectorSize)
+		total := fatReservedCount + fatCount*fatSectors + rootSectors + clusters*spc
+
+		return fatLayout{
+			bits:              bits,
+			sectorsPerCluster: spc,
+			clusters:          clusters,
+			fatSectors:        fatSectors,
+			totalSectors:      total,
+		}, nil
+	}
+
+	return fatLayout{}, &Error{Op: "render", Err: ErrPayloadTooLarge}
+}
+
+func encodeFAT(fat []uint32, layout fatLayout) []byte {
+	b := make([]byte, int(layout.fatSectors)*fatSectorSize)
+
+	if layout.bits == 16 {
+		for i, v := range fat {
+			binary.LittleEndian.PutUint16(b[2*i:], uint16(v))
+		}
+
+		return b
+	}
+
+	for i, v := range fat {
+		v &= 0xfff
+		offset := i * 3 / 2
+		if i%2 == 0 {
+			b[offset] = byte(v)
+			b[offset+1] = b[offset+1]&0xf0 | byte(v>>8)
+		} else {
+			b[offset] = b[offset]&0x0f | byte(v<<4)
+			b[offset+1] = byte(v >> 4)
+		}
+	}
+
+	return b
+}
+
+func fatDirectoryEntries(dir *fatNode) [][]byte {
+	entries := make([][]byte, 0, len(dir.children))
+	for _, child := range dir.children {
+		if child.longName {
+			entries = append(entries, fatLongEntries(child.name, child.shortName)...)
+		}
+
+		attr := byte(fatAttrArchive)
+		size := uint32(len(child.data))
+		if child.mode.IsDir() {
+			attr = fatAttrDirectory
+			size = 0
+		}
+
+		entries = append(entries, fatShortEntry(child.shortName, attr, child.cluster, size))
+	}
+
+	return entries
+}
+
+func fatLabelEntry(label string) []byte {
+	var name [11]byte
+	copy(name[:], padded(label, fatMaxLabel))
+
+	return fatShortEntry(name, fatAttrVolumeID, 0, 0)
+}
+
+func fatShortEntry(name [11]byte, attr byte, cluster uint32, size uint32) []byte {
+	e := make([]byte, fatEntrySize)
+	copy(e[0:11], name[:])
+	e[11] = attr
+	binary.LittleEndian.PutUint16(e[16:18], fatDate)
+	binary.LittleEndian.PutUint16(e[18:20], fatDate)
+	binary.LittleEndian.PutUint16(e[20:22], uint16(cluster>>16))
+	binary.LittleEndian.PutUint16(e[24:26], fatDate)
+	binary.LittleEndian.PutUint16(e[26:28], uint16(cluster))
+	binary.LittleEndian.PutUint32(e[28:32], size)
+
+	return e
+}
+
+func fatLongEntries(name string, short [11]byte) [][]byte {
+	var sum byte
+	for _, c := range short {
+		sum = (sum&1)<<7 + sum>>1 + c
+	}
+
+	units := utf16.Encode([]rune(name))
+	if len(units)%13 != 0 {
+		units = append(units, 0)
+	}
+	for len(units)%13 != 0 {
+		units = append(units, 0xffff)
+	}
+
+	n := len(units) / 13
+	entries := make([][]byte, 0, n)
+	for seq := n; seq >= 1; seq-- {
+		chunk := units[(seq-1)*13 : seq*13]
+
+		e := make([]byte, fatEntrySize)
+		e[0] = byte(seq)
+		if seq == n {
+			e[0] |= 0x40
+		}
+		e[11] = fatAttrLongName
+		e[13] = sum
+
+		for i, u := range chunk {
+			var offset int
+			switch {
+			case i < 5:
+				offset = 1 + 2*i
+			case i < 11:
+				offset = 14 + 2*(i-5)
+			default:
+				offset = 28 + 2*(i-11)
+			}
+			binary.LittleEndian.PutUint16(e[offset:], u)
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+func fatExactShortName(name string) ([11]byte, bool) {
+	var short [11]byte
+
+	base, ext, _ := strings.Cut(name, ".")
+	if base == "" || len(base) > 8 || len(ext) > 3 || strings.Contains(ext, ".") {
+		return short, false
+	}
+
+	for _, r := range base + ext {
+		if !isFATShortChar(r) {
+			return short, false
+		}
+	}
+
+	copy(short[:], padded(base, 8))
+	copy(short[8:], padded(ext, 3))
+
+	return short, true
+}
+
+func fatGeneratedShortName(name string, used map[[11]byte]bool) [11]byte {
+	base, ext := name, ""
+	if i := strings.LastIndex(name, "."); i > 0 {
+		base, ext = name[:i], name[i+1:]
+	}
+
+	clean := func(s string) string {
+		s = strings.ToUpper(strings.ReplaceAll(strings.TrimLeft(s, "."), " ", ""))
+		s = strings.ReplaceAll(s, ".", "")
+
+		return strings.Map(func(r rune) rune {
+			if isFATShortChar(r) {
+				return r
+			}
+
+			return '_'
+		}, s)
+	}
+
+	base, ext = clean(base), truncate(clean(ext), 3)
+	if base == "" {
+		base = "_"
+	}
+
+	var short [11]byte
+	for n := 1; ; n++ {
+		tail := fmt.Sprintf("~%d", n)
+		copy(short[:], padded(truncate(base, 8-len(tail))+tail, 8))
+		copy(short[8:], padded(ext, 3))
+
+		if !used[short] {
+			return short
+		}
+	}
+}
+
+func isFATShortChar(r rune) bool {
+	return r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune(fatShortNameSpecials, r)
+}
@@ -0,0 +1,338 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fatContent struct {
+	bits  int
+	label string
+	files map[string][]byte
+}
+
+func readFAT(b []byte) fatContent {
+	bps := int(binary.LittleEndian.Uint16(b[11:13]))
+	spc := int(b[13])
+	reserved := int(binary.LittleEndian.Uint16(b[14:16]))
+	nfats := int(b[16])
+	rootEntries := int(binary.LittleEndian.Uint16(b[17:19]))
+	total := int(binary.LittleEndian.Uint16(b[19:21]))
+	if total == 0 {
+		total = int(binary.LittleEndian.Uint32(b[32:36]))
+	}
+	fatSectors := int(binary.LittleEndian.Uint16(b[22:24]))
+
+	rootStart := reserved + nfats*fatSectors
+	dataStart := rootStart + (rootEntries*32+bps-1)/bps
+	clusters := (total - dataStart) / spc
+
+	content := fatContent{bits: 12, files: make(map[string][]byte)}
+	if clusters >= 4085 {
+		content.bits = 16
+	}
+
+	fat := b[reserved*bps:]
+	entry := func(cluster int) int {
+		if content.bits == 16 {
+			return int(binary.LittleEndian.Uint16(fat[2*cluster:]))
+		}
+
+		v := int(binary.LittleEndian.Uint16(fat[cluster*3/2:]))
+		if cluster%2 == 0 {
+			return v & 0xfff
+		}
+		return v >> 4
+	}
+	eoc := 0xff8
+	if content.bits == 16 {
+		eoc = 0xfff8
+	}
+
+	chain := func(cluster int) []byte {
+		data := make([]byte, 0)
+		for cluster >= 2 && cluster < eoc {
+			offset := (dataStart + (cluster-2)*spc) * bps
+			data = append(data, b[offset:offset+spc*bps]...)
+			cluster = entry(cluster)
+		}
+
+		return data
+	}
+
+	var walk func(entries []byte, prefix string)
+	walk = func(entries []byte, prefix string) {
+		var long []uint16
+		for offset := 0; offset+32 <= len(entries); offset += 32 {
+			e := entries[offset : offset+32]
+			if e[0] == 0 {
+				return
+			}
+
+			if e[11] == 0x0f {
+				var units []uint16
+				for _, o := range []int{1, 3, 5, 7, 9, 14, 16, 18, 20, 22, 24, 28, 30} {
+					units = append(units, binary.LittleEndian.Uint16(e[o:]))
+				}
+				long = append(units, long...)
+				continue
+			}
+
+			if e[11]&0x08 != 0 {
+				content.label = strings.TrimRight(string(e[0:11]), " ")
+				continue
+			}
+
+			name := strings.TrimRight(string(e[0:8]), " ")
+			if ext := strings.TrimRight(string(e[8:11]), " "); ext != "" {
+				name += "." + ext
+			}
+			if long != nil {
+				for i, u := range long {
+					if u == 0 {
+						long = long[:i]
+						break
+					}
+				}
+				name = string(utf16.Decode(long))
+				long = nil
+			}
+
+			if name == "." || name == ".." {
+				continue
+			}
+
+			cluster := int(binary.LittleEndian.Uint16(e[26:28]))
+			if e[11]&0x10 != 0 {
+				walk(chain(cluster), prefix+name+"/")
+				continue
+			}
+
+			size := int(binary.LittleEndian.Uint32(e[28:32]))
+			content.files[prefix+name] = chain(cluster)[:size]
+		}
+	}
+	walk(b[rootStart*bps:dataStart*bps], "")
+
+	return content
+}
+
+func TestNewFATImage(t *testing.T) {
+	type args struct {
+		label string
+	}
+
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case",
+			args: args{
+				label: "CIDATA",
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: empty",
+			args: args{
+				label: "",
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrInvalidVolumeID},
+			},
+		},
+		{
+			name: "negative case: too long",
+			args: args{
+				label: "CIDATA-CIDATA",
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrInvalidVolumeID},
+			},
+		},
+		{
+			name: "negative case: invalid character",
+			args: args{
+				label: "CI.DATA",
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrInvalidVolumeID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFATImage(tt.args.label)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestFATImage_Render(t *testing.T) {
+	type expected struct {
+		res fatContent
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		image    FATImage
+		expected expected
+	}{
+		{
+			name: "positive case: nocloud seed",
+			image: func() FATImage {
+				img, _ := NewFATImage("CIDATA")
+
+				seed := Seed{
+					UserData: func() Multipart {
+						m, _ := NewMultipart()
+						m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London")))
+						return m
+					}(),
+					VendorData: func() Multipart {
+						m, _ := NewMultipart()
+						return m
+					}(),
+					MetaData: MetaData{InstanceID: "iid-local01"},
+				}
+				_ = seed.Write(img)
+				_ = img.WriteFile("network-config", []byte("version: 2\n"), 0o644)
+
+				return img
+			}(),
+			expected: expected{
+				res: fatContent{
+					bits:  12,
+					label: "CIDATA",
+					files: map[string][]byte{
+						"user-data": []byte("Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+							"Mime-Version: 1.0\r\n" +
+							"\r\n" +
+							"--+Go+User+Data+Boundary==\r\n" +
+							"Content-Transfer-Encoding: 7bit\r\n" +
+							"Content-Type: text/cloud-config; charset=us-ascii\r\n" +
+							"\r\n" +
+							"#cloud-config\n" +
+							"timezone: Europe/London\r\n" +
+							"\r\n" +
+							"--+Go+User+Data+Boundary==--\r\n"),
+						"meta-data": []byte("instance-id: iid-local01\n"),
+						"vendor-data": []byte("Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+							"Mime-Version: 1.0\r\n" +
+							"\r\n" +
+							"--+Go+User+Data+Boundary==--\r\n"),
+						"network-config": []byte("version: 2\n"),
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: nested, short names and long names",
+			image: func() FATImage {
+				img, _ := NewFATImage("config-2")
+
+				_ = img.WriteFile("README", []byte("readme"), 0o644)
+				_ = img.WriteFile("openstack/latest/meta_data.json", []byte("{}"), 0o644)
+				_ = img.WriteFile("openstack/latest/network_data.json", []byte("{}"), 0o644)
+				_ = img.WriteFile("openstack/latest/user_data", []byte{}, 0o644)
+				_ = img.WriteFile("openstack/2012-08-10/user_data", bytes.Repeat([]byte("a"), 5000), 0o644)
+
+				return img
+			}(),
+			expected: expected{
+				res: fatContent{
+					bits:  12,
+					label: "config-2",
+					files: map[string][]byte{
+						"README":                             []byte("readme"),
+						"openstack/latest/meta_data.json":    []byte("{}"),
+						"openstack/latest/network_data.json": []byte("{}"),
+						"openstack/latest/user_data":         {},
+						"openstack/2012-08-10/user_data":     bytes.Repeat([]byte("a"), 5000),
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: fat16",
+			image: func() FATImage {
+				img, _ := NewFATImage("CIDATA")
+				_ = img.WriteFile("user-data", bytes.Repeat([]byte("a"), 4*1024*1024), 0o644)
+
+				return img
+			}(),
+			expected: expected{
+				res: fatContent{
+					bits:  16,
+					label: "CIDATA",
+					files: map[string][]byte{
+						"user-data": bytes.Repeat([]byte("a"), 4*1024*1024),
+					},
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.image.Render(buf)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, readFAT(buf.Bytes()))
+
+				again := new(bytes.Buffer)
+				assert.NoError(t, tt.image.Render(again))
+				assert.Equal(t, buf.Bytes(), again.Bytes())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	gceUserData         = "user-data"
+	gceUserDataEncoding = "user-data-encoding"
+	gceStartupScript    = "startup-script"
+	gceEncodingBase64   = "base64"
+	gceDelimiter        = "GOUSERDATA_STARTUP_EOF"
+	gceMarkerDir        = "/var/lib/gouserdata/startup-script"
+	gceHashSize         = 8
+	gceInstanceIDURL    = "http://metadata.google.internal/computeMetadata/v1/instance/id"
+
+	// limit applies to each metadata value
+	gceMaxValueSize = 256 * 1024
+)
+
+type GCEMetadataItem struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type GCEMetadata struct {
+	UserData      Multipart
+	StartupScript bool
+}
+
+func (g *GCEMetadata) Items() ([]GCEMetadataItem, error) {
+	if g.UserData == nil {
+		return []GCEMetadataItem{}, nil
+	}
+
+	if g.StartupScript {
+		script, err := g.startupScript()
+		if err != nil {
+			logger.Println("failed to build gce metadata", "func", getFuncName(), "error", err)
+			return nil, err
+		}
+
+		if script == nil {
+			return []GCEMetadataItem{}, nil
+		}
+
+		if len(script) > gceMaxValueSize {
+			err := &Error{Op: "encode", Err: ErrPayloadTooLarge}
+			logger.Println("failed to build gce metadata", "func", getFuncName(), "key", gceStartupScript, "size", len(script), "error", err)
+			return nil, err
+		}
+
+		return []GCEMetadataItem{{Key: gceStartupScript, Value: string(script)}}, nil
+	}
+
+	data, err := renderBytes(g.UserData)
+	if err != nil {
+		logger.Println("failed to build gce metadata", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	if utf8.Valid(data) && len(data) <= gceMaxValueSize {
+		return []GCEMetadataItem{{Key: gceUserData, Value: string(data)}}, nil
+	}
+
+	// cloud-init decompresses gzip payloads after decoding
+	compressed, err := gzipBytes(data)
+	if err != nil {
+		logger.Println("failed to build gce metadata", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	value := base64.StdEncoding.EncodeToString(compressed)
+	if len(value) > gceMaxValueSize {
+		err := &Error{Op: "encode", Err: ErrPayloadTooLarge}
+		logger.Println("failed to build gce metadata", "func", getFuncName(), "key", gceUserData, "size", len(value), "error", err)
+		return nil, err
+	}
+
+	return []GCEMetadataItem{
+		{Key: gceUserData, Value: value},
+		{Key: gceUserDataEncoding, Value: gceEncodingBase64},
+	}, nil
+}
+
+func (g *GCEMetadata) JSON() ([]byte, error) {
+	items, err := g.Items()
+	if err != nil {
+		logger.Println("failed to build gce metadata", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	b, err := json.Marshal(&struct {
+		Items []GCEMetadataItem `json:"items"`
+	}{Items: items})
+	if err != nil {
+		err = &Error{Op: "encode", Err: err}
+		logger.Println("failed to build gce metadata", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (g *GCEMetadata) startupScript() ([]byte, error) {
+	parts := g.UserData.Parts()
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	// images without cloud-init ignore user-data, so every part has to become part of the startup script
+	rerun, perInstance := true, false
+	for _, p := range parts {
+		spec, ok := lookupMediaType(p.MediaType())
+		if !ok || !spec.Script {
+			err := &Error{Op: "encode", Err: ErrUnsupportedMediaType}
+			logger.Println("failed to build startup script", "func", getFuncName(), "mediaType", p.MediaType(), "error", err)
+			return nil, err
+		}
+
+		if !bytes.HasPrefix(p.Body(), []byte("#!")) {
+			err := &Error{Op: "encode", Err: ErrMissingShebang}
+			logger.Println("failed to build startup script", "func", getFuncName(), "mediaType", p.MediaType(), "error", err)
+			return nil, err
+		}
+
+		switch spec.Frequency {
+		case FrequencyAlways, FrequencyPerBoot:
+		case FrequencyPerInstance:
+			rerun, perInstance = false, true
+		default:
+			rerun = false
+		}
+	}
+
+	if len(parts) == 1 && rerun {
+		return parts[0].Body(), nil
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprint(buf, "#!/bin/sh\n")
+	fmt.Fprint(buf, "set -e\n")
+	fmt.Fprint(buf, "dir=\"$(mktemp -d)\"\n")
+	fmt.Fprint(buf, "trap 'rm -rf \"${dir}\"' EXIT\n")
+
+	// startup scripts run on every boot, so markers keep per-instance and once scripts from rerunning
+	if perInstance {
+		fmt.Fprintf(buf, "instance=\"$(curl -fsS -H 'Metadata-Flavor: Google' %s)\"\n", gceInstanceIDURL)
+		fmt.Fprintf(buf, "mkdir -p \"%s/${instance}\"\n", gceMarkerDir)
+	} else if !rerun {
+		fmt.Fprintf(buf, "mkdir -p \"%s\"\n", gceMarkerDir)
+	}
+
+	for i, p := range parts {
+		for _, line := range strings.Split(string(p.Body()), "\n") {
+			if strings.TrimRight(line, "\r") == gceDelimiter {
+				err := &Error{Op: "encode", Err: ErrInvalidBody}
+				logger.Println("failed to build startup script", "func", getFuncName(), "mediaType", p.MediaType(), "error", err)
+				return nil, err
+			}
+		}
+
+		spec, _ := lookupMediaType(p.MediaType())
+
+		marker := ""
+		switch spec.Frequency {
+		case FrequencyAlways, FrequencyPerBoot:
+		case FrequencyPerInstance:
+			marker = fmt.Sprintf("%s/${instance}/%s.done", gceMarkerDir, gceMarkerName(p))
+		default:
+			marker = fmt.Sprintf("%s/%s.done", gceMarkerDir, gceMarkerName(p))
+		}
+
+		if marker != "" {
+			fmt.Fprintf(buf, "if [ ! -e \"%s\" ]; then\n", marker)
+		}
+
+		fmt.Fprintf(buf, "cat > \"${dir}/%d\" <<'%s'\n", i, gceDelimiter)
+		writeHeredoc(buf, p.Body(), gceDelimiter)
+		fmt.Fprintf(buf, "chmod +x \"${dir}/%d\"\n", i)
+		fmt.Fprintf(buf, "\"${dir}/%d\"\n", i)
+
+		if marker != "" {
+			fmt.Fprintf(buf, "touch \"%s\"\n", marker)
+			fmt.Fprint(buf, "fi\n")
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// markers follow the script content, so adding or reordering parts keeps them attached to the right script
+func gceMarkerName(p Part) string {
+	sum := sha256.Sum256(p.Body())
+
+	return hex.EncodeToString(sum[:gceHashSize])
+}
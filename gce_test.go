@@ -0,0 +1,354 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGCEMetadata_Items(t *testing.T) {
+	type expected struct {
+		res []GCEMetadataItem
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		metadata GCEMetadata
+		expected expected
+	}{
+		{
+			name: "positive case: user-data",
+			metadata: GCEMetadata{
+				UserData: mustNewMultipart(),
+			},
+			expected: expected{
+				res: []GCEMetadataItem{
+					{
+						Key: "user-data",
+						Value: "Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+							"Mime-Version: 1.0\r\n" +
+							"\r\n" +
+							"--+Go+User+Data+Boundary==--\r\n",
+					},
+				},
+			},
+		},
+		{
+			name: "positive case: single startup-script",
+			metadata: GCEMetadata{
+				UserData:      mustNewMultipart(mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/bash\n"+"echo hello\n"))),
+				StartupScript: true,
+			},
+			expected: expected{
+				res: []GCEMetadataItem{
+					{Key: "startup-script", Value: "#!/bin/bash\n" + "echo hello\n"},
+				},
+			},
+		},
+		{
+			name: "positive case: combined startup-script",
+			metadata: GCEMetadata{
+				UserData: mustNewMultipart(
+					mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/bash\n"+"echo hello\n")),
+					mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/usr/bin/env python3\n"+"print('world')")),
+				),
+				StartupScript: true,
+			},
+			expected: expected{
+				res: []GCEMetadataItem{
+					{
+						Key: "startup-script",
+						Value: "#!/bin/sh\n" +
+							"set -e\n" +
+							"dir=\"$(mktemp -d)\"\n" +
+							"trap 'rm -rf \"${dir}\"' EXIT\n" +
+							"cat > \"${dir}/0\" <<'GOUSERDATA_STARTUP_EOF'\n" +
+							"#!/bin/bash\n" +
+							"echo hello\n" +
+							"GOUSERDATA_STARTUP_EOF\n" +
+							"chmod +x \"${dir}/0\"\n" +
+							"\"${dir}/0\"\n" +
+							"cat > \"${dir}/1\" <<'GOUSERDATA_STARTUP_EOF'\n" +
+							"#!/usr/bin/env python3\n" +
+							"print('world')\n" +
+							"GOUSERDATA_STARTUP_EOF\n" +
+							"chmod +x \"${dir}/1\"\n" +
+							"\"${dir}/1\"\n",
+					},
+				},
+			},
+		},
+		{
+			name: "positive case: per-instance and once startup-script",
+			metadata: GCEMetadata{
+				UserData: mustNewMultipart(
+					mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\n"+"echo instance\n")),
+					mustNewPart(MediaTypeXShellscriptPerOnce, []byte("#!/bin/sh\n"+"echo once\n")),
+					mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/sh\n"+"echo boot\n")),
+				),
+				StartupScript: true,
+			},
+			expected: expected{
+				res: []GCEMetadataItem{
+					{
+						Key: "startup-script",
+						Value: "#!/bin/sh\n" +
+							"set -e\n" +
+							"dir=\"$(mktemp -d)\"\n" +
+							"trap 'rm -rf \"${dir}\"' EXIT\n" +
+							"instance=\"$(curl -fsS -H 'Metadata-Flavor: Google' http://metadata.google.internal/computeMetadata/v1/instance/id)\"\n" +
+							"mkdir -p \"/var/lib/gouserdata/startup-script/${instance}\"\n" +
+							"if [ ! -e \"/var/lib/gouserdata/startup-script/${instance}/2e808344a66e2b22.done\" ]; then\n" +
+							"cat > \"${dir}/0\" <<'GOUSERDATA_STARTUP_EOF'\n" +
+							"#!/bin/sh\n" +
+							"echo instance\n" +
+							"GOUSERDATA_STARTUP_EOF\n" +
+							"chmod +x \"${dir}/0\"\n" +
+							"\"${dir}/0\"\n" +
+							"touch \"/var/lib/gouserdata/startup-script/${instance}/2e808344a66e2b22.done\"\n" +
+							"fi\n" +
+							"if [ ! -e \"/var/lib/gouserdata/startup-script/ec725f6d5f3fa70b.done\" ]; then\n" +
+							"cat > \"${dir}/1\" <<'GOUSERDATA_STARTUP_EOF'\n" +
+							"#!/bin/sh\n" +
+							"echo once\n" +
+							"GOUSERDATA_STARTUP_EOF\n" +
+							"chmod +x \"${dir}/1\"\n" +
+							"\"${dir}/1\"\n" +
+							"touch \"/var/lib/gouserdata/startup-script/ec725f6d5f3fa70b.done\"\n" +
+							"fi\n" +
+							"cat > \"${dir}/2\" <<'GOUSERDATA_STARTUP_EOF'\n" +
+							"#!/bin/sh\n" +
+							"echo boot\n" +
+							"GOUSERDATA_STARTUP_EOF\n" +
+							"chmod +x \"${dir}/2\"\n" +
+							"\"${dir}/2\"\n",
+					},
+				},
+			},
+		},
+		{
+			name: "positive case: single once startup-script",
+			metadata: GCEMetadata{
+				UserData:      mustNewMultipart(mustNewPart(MediaTypeXShellscriptPerOnce, []byte("#!/bin/sh\n"+"echo once\n"))),
+				StartupScript: true,
+			},
+			expected: expected{
+				res: []GCEMetadataItem{
+					{
+						Key: "startup-script",
+						Value: "#!/bin/sh\n" +
+							"set -e\n" +
+							"dir=\"$(mktemp -d)\"\n" +
+							"trap 'rm -rf \"${dir}\"' EXIT\n" +
+							"mkdir -p \"/var/lib/gouserdata/startup-script\"\n" +
+							"if [ ! -e \"/var/lib/gouserdata/startup-script/ec725f6d5f3fa70b.done\" ]; then\n" +
+							"cat > \"${dir}/0\" <<'GOUSERDATA_STARTUP_EOF'\n" +
+							"#!/bin/sh\n" +
+							"echo once\n" +
+							"GOUSERDATA_STARTUP_EOF\n" +
+							"chmod +x \"${dir}/0\"\n" +
+							"\"${dir}/0\"\n" +
+							"touch \"/var/lib/gouserdata/startup-script/ec725f6d5f3fa70b.done\"\n" +
+							"fi\n",
+					},
+				},
+			},
+		},
+		{
+			name: "positive case: once startup-scripts",
+			metadata: GCEMetadata{
+				UserData: mustNewMultipart(
+					mustNewPart(MediaTypeXShellscriptPerOnce, []byte("#!/bin/sh\n"+"echo first\n")),
+					mustNewPart(MediaTypeXShellscriptPerOnce, []byte("#!/bin/sh\n"+"echo second\n")),
+				),
+				StartupScript: true,
+			},
+			expected: expected{
+				res: []GCEMetadataItem{
+					{
+						Key: "startup-script",
+						Value: "#!/bin/sh\n" +
+							"set -e\n" +
+							"dir=\"$(mktemp -d)\"\n" +
+							"trap 'rm -rf \"${dir}\"' EXIT\n" +
+							"mkdir -p \"/var/lib/gouserdata/startup-script\"\n" +
+							"if [ ! -e \"/var/lib/gouserdata/startup-script/33ba170df335478d.done\" ]; then\n" +
+							"cat > \"${dir}/0\" <<'GOUSERDATA_STARTUP_EOF'\n" +
+							"#!/bin/sh\n" +
+							"echo first\n" +
+							"GOUSERDATA_STARTUP_EOF\n" +
+							"chmod +x \"${dir}/0\"\n" +
+							"\"${dir}/0\"\n" +
+							"touch \"/var/lib/gouserdata/startup-script/33ba170df335478d.done\"\n" +
+							"fi\n" +
+							"if [ ! -e \"/var/lib/gouserdata/startup-script/e96b178c351d57d5.done\" ]; then\n" +
+							"cat > \"${dir}/1\" <<'GOUSERDATA_STARTUP_EOF'\n" +
+							"#!/bin/sh\n" +
+							"echo second\n" +
+							"GOUSERDATA_STARTUP_EOF\n" +
+							"chmod +x \"${dir}/1\"\n" +
+							"\"${dir}/1\"\n" +
+							"touch \"/var/lib/gouserdata/startup-script/e96b178c351d57d5.done\"\n" +
+							"fi\n",
+					},
+				},
+			},
+		},
+		{
+			name: "positive case: reordered once startup-scripts",
+			metadata: GCEMetadata{
+				UserData: mustNewMultipart(
+					mustNewPart(MediaTypeXShellscriptPerOnce, []byte("#!/bin/sh\n"+"echo second\n")),
+					mustNewPart(MediaTypeXShellscriptPerOnce, []byte("#!/bin/sh\n"+"echo first\n")),
+				),
+				StartupScript: true,
+			},
+			expected: expected{
+				res: []GCEMetadataItem{
+					{
+						Key: "startup-script",
+						Value: "#!/bin/sh\n" +
+							"set -e\n" +
+							"dir=\"$(mktemp -d)\"\n" +
+							"trap 'rm -rf \"${dir}\"' EXIT\n" +
+							"mkdir -p \"/var/lib/gouserdata/startup-script\"\n" +
+							"if [ ! -e \"/var/lib/gouserdata/startup-script/e96b178c351d57d5.done\" ]; then\n" +
+							"cat > \"${dir}/0\" <<'GOUSERDATA_STARTUP_EOF'\n" +
+							"#!/bin/sh\n" +
+							"echo second\n" +
+							"GOUSERDATA_STARTUP_EOF\n" +
+							"chmod +x \"${dir}/0\"\n" +
+							"\"${dir}/0\"\n" +
+							"touch \"/var/lib/gouserdata/startup-script/e96b178c351d57d5.done\"\n" +
+							"fi\n" +
+							"if [ ! -e \"/var/lib/gouserdata/startup-script/33ba170df335478d.done\" ]; then\n" +
+							"cat > \"${dir}/1\" <<'GOUSERDATA_STARTUP_EOF'\n" +
+							"#!/bin/sh\n" +
+							"echo first\n" +
+							"GOUSERDATA_STARTUP_EOF\n" +
+							"chmod +x \"${dir}/1\"\n" +
+							"\"${dir}/1\"\n" +
+							"touch \"/var/lib/gouserdata/startup-script/33ba170df335478d.done\"\n" +
+							"fi\n",
+					},
+				},
+			},
+		},
+		{
+			name: "positive case: empty startup-script",
+			metadata: GCEMetadata{
+				UserData:      mustNewMultipart(),
+				StartupScript: true,
+			},
+			expected: expected{
+				res: []GCEMetadataItem{},
+			},
+		},
+		{
+			name: "negative case: cloud-config as startup-script",
+			metadata: GCEMetadata{
+				UserData:      mustNewMultipart(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"runcmd: []\n"))),
+				StartupScript: true,
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrUnsupportedMediaType},
+			},
+		},
+		{
+			name: "negative case: delimiter in startup-script",
+			metadata: GCEMetadata{
+				UserData: mustNewMultipart(
+					mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/sh\n"+"GOUSERDATA_STARTUP_EOF\n")),
+					mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/sh\n"+"echo hello\n")),
+				),
+				StartupScript: true,
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrInvalidBody},
+			},
+		},
+		{
+			name: "negative case: too large",
+			metadata: GCEMetadata{
+				UserData: func() Multipart {
+					b := make([]byte, 256*1024)
+					_, _ = rand.Read(b)
+					return mustNewMultipart(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"# "+base64.StdEncoding.EncodeToString(b))))
+				}(),
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrPayloadTooLarge},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.metadata.Items()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestGCEMetadata_Items_compressed(t *testing.T) {
+	m, _ := NewMultipart()
+	m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+strings.Repeat("# padding\n", 32*1024))))
+
+	g := &GCEMetadata{UserData: m}
+	actual, err := g.Items()
+
+	assert.NoError(t, err)
+	if assert.Len(t, actual, 2) {
+		assert.Equal(t, GCEMetadataItem{Key: "user-data-encoding", Value: "base64"}, actual[1])
+
+		data, err := base64.StdEncoding.DecodeString(actual[0].Value)
+		assert.NoError(t, err)
+
+		parsed, err := Parse(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, m.Parts(), parsed.Parts())
+	}
+}
+
+func TestGCEMetadata_JSON(t *testing.T) {
+	m, _ := NewMultipart()
+	m.Append(mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/bash\n"+"echo hello\n")))
+
+	g := &GCEMetadata{UserData: m, StartupScript: true}
+	actual, err := g.JSON()
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"items":[{"key":"startup-script","value":"#!/bin/bash\necho hello\n"}]}`, string(actual))
+}
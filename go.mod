@@ -5,10 +5,10 @@ go 1.19
 require (
 	github.com/stretchr/testify v1.8.0
 	golang.org/x/exp v0.0.0-20230118134722-a68e582fa157
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,189 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	guestInfoPrefix         = "guestinfo."
+	guestInfoUserData       = "userdata"
+	guestInfoMetaData       = "metadata"
+	guestInfoVendorData     = "vendordata"
+	guestInfoEncodingSuffix = ".encoding"
+
+	// conservative limit for a single extraConfig value
+	guestInfoMaxSize = 64 * 1024
+)
+
+const (
+	GuestInfoEncodingBase64     = "base64"
+	GuestInfoEncodingGzipBase64 = "gzip+base64"
+)
+
+type GuestInfoMetaData struct {
+	InstanceID     string
+	LocalHostname  string
+	PublicKeysData string
+	Network        Renderer
+}
+
+func (m *GuestInfoMetaData) Render(w io.Writer) error {
+	if m.InstanceID == "" {
+		err := &Error{Op: "render", Err: ErrMissingInstanceID}
+		logger.Println("failed to render guestinfo meta-data", "func", getFuncName(), "metaData", m, "error", err)
+		return err
+	}
+
+	doc := struct {
+		InstanceID      string `yaml:"instance-id"`
+		LocalHostname   string `yaml:"local-hostname,omitempty"`
+		PublicKeysData  string `yaml:"public-keys-data,omitempty"`
+		Network         string `yaml:"network,omitempty"`
+		NetworkEncoding string `yaml:"network.encoding,omitempty"`
+	}{
+		InstanceID:     m.InstanceID,
+		LocalHostname:  m.LocalHostname,
+		PublicKeysData: m.PublicKeysData,
+	}
+
+	if m.Network != nil {
+		b, err := renderBytes(m.Network)
+		if err != nil {
+			logger.Println("failed to render guestinfo meta-data", "func", getFuncName(), "metaData", m, "error", err)
+			return err
+		}
+
+		doc.Network, doc.NetworkEncoding, err = encodeGuestInfo(b)
+		if err != nil {
+			logger.Println("failed to render guestinfo meta-data", "func", getFuncName(), "metaData", m, "error", err)
+			return err
+		}
+	}
+
+	enc := yaml.NewEncoder(w)
+	if err := enc.Encode(&doc); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render guestinfo meta-data", "func", getFuncName(), "metaData", m, "error", err)
+		return err
+	}
+
+	if err := enc.Close(); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render guestinfo meta-data", "func", getFuncName(), "metaData", m, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+type GuestInfo struct {
+	UserData   Multipart
+	MetaData   GuestInfoMetaData
+	VendorData Multipart
+	MaxSize    int
+}
+
+func (g *GuestInfo) Values() (map[string]string, error) {
+	maxSize := g.MaxSize
+	if maxSize <= 0 {
+		maxSize = guestInfoMaxSize
+	}
+
+	values := make(map[string]string, 6)
+	for _, item := range []struct {
+		key string
+		r   Renderer
+	}{
+		{key: guestInfoMetaData, r: &g.MetaData},
+		{key: guestInfoUserData, r: g.UserData},
+		{key: guestInfoVendorData, r: g.VendorData},
+	} {
+		if item.r == nil {
+			continue
+		}
+
+		b, err := renderBytes(item.r)
+		if err != nil {
+			logger.Println("failed to encode guestinfo", "func", getFuncName(), "key", item.key, "error", err)
+			return nil, err
+		}
+
+		value, encoding, err := encodeGuestInfo(b)
+		if err != nil {
+			logger.Println("failed to encode guestinfo", "func", getFuncName(), "key", item.key, "error", err)
+			return nil, err
+		}
+
+		if len(value) > maxSize {
+			err := &Error{Op: "encode", Err: ErrPayloadTooLarge}
+			logger.Println("failed to encode guestinfo", "func", getFuncName(), "key", item.key, "size", len(value), "maxSize", maxSize, "error", err)
+			return nil, err
+		}
+
+		values[guestInfoPrefix+item.key] = value
+		values[guestInfoPrefix+item.key+guestInfoEncodingSuffix] = encoding
+	}
+
+	return values, nil
+}
+
+func (g *GuestInfo) Render(w io.Writer) error {
+	values, err := g.Values()
+	if err != nil {
+		logger.Println("failed to render guestinfo", "func", getFuncName(), "error", err)
+		return err
+	}
+
+	keys := maps.Keys(values)
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s = \"%s\"\n", key, values[key]); err != nil {
+			err = &Error{Op: "render", Err: err}
+			logger.Println("failed to render guestinfo", "func", getFuncName(), "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodeGuestInfo(data []byte) (string, string, error) {
+	compressed, err := gzipBytes(data)
+	if err != nil {
+		return "", "", err
+	}
+
+	plain := base64.StdEncoding.EncodeToString(data)
+	if encoded := base64.StdEncoding.EncodeToString(compressed); len(encoded) < len(plain) {
+		return encoded, GuestInfoEncodingGzipBase64, nil
+	}
+
+	return plain, GuestInfoEncodingBase64, nil
+}
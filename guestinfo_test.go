@@ -0,0 +1,190 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuestInfoMetaData_Render(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		metaData GuestInfoMetaData
+		expected expected
+	}{
+		{
+			name: "positive case",
+			metaData: GuestInfoMetaData{
+				InstanceID:    "iid-vmware01",
+				LocalHostname: "vm01",
+			},
+			expected: expected{
+				res: "instance-id: iid-vmware01\n" +
+					"local-hostname: vm01\n",
+			},
+		},
+		{
+			name: "positive case: network",
+			metaData: GuestInfoMetaData{
+				InstanceID: "iid-vmware01",
+				Network: &NetworkConfigV2{
+					Ethernets: map[string]NetworkV2Ethernet{
+						"eth0": {NetworkV2Device: NetworkV2Device{DHCP4: true}},
+					},
+				},
+			},
+			expected: expected{
+				res: "instance-id: iid-vmware01\n" +
+					// base64.StdEncoding.EncodeToString([]byte("version: 2\nethernets:\n  eth0:\n    dhcp4: true\n"))
+					"network: dmVyc2lvbjogMgpldGhlcm5ldHM6CiAgZXRoMDoKICAgIGRoY3A0OiB0cnVlCg==\n" +
+					"network.encoding: base64\n",
+			},
+		},
+		{
+			name: "negative case: missing instance id",
+			metaData: GuestInfoMetaData{
+				LocalHostname: "vm01",
+			},
+			expected: expected{
+				err: &Error{Op: "render", Err: ErrMissingInstanceID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.metaData.Render(buf)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, buf.String())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestGuestInfo_Values(t *testing.T) {
+	padded := func() Multipart {
+		m, _ := NewMultipart()
+		m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+strings.Repeat("# padding\n", 50))))
+		return m
+	}
+
+	type expected struct {
+		res map[string]string
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		guestInfo GuestInfo
+		expected  expected
+	}{
+		{
+			name: "positive case: base64",
+			guestInfo: GuestInfo{
+				MetaData: GuestInfoMetaData{InstanceID: "iid-vmware01", LocalHostname: "vm01"},
+			},
+			expected: expected{
+				res: map[string]string{
+					// base64.StdEncoding.EncodeToString([]byte("instance-id: iid-vmware01\nlocal-hostname: vm01\n"))
+					"guestinfo.metadata":          "aW5zdGFuY2UtaWQ6IGlpZC12bXdhcmUwMQpsb2NhbC1ob3N0bmFtZTogdm0wMQo=",
+					"guestinfo.metadata.encoding": "base64",
+				},
+			},
+		},
+		{
+			name: "positive case: gzip+base64",
+			guestInfo: GuestInfo{
+				UserData: padded(),
+				MetaData: GuestInfoMetaData{InstanceID: "iid-vmware01", LocalHostname: "vm01"},
+			},
+			expected: expected{
+				res: map[string]string{
+					"guestinfo.metadata":          "aW5zdGFuY2UtaWQ6IGlpZC12bXdhcmUwMQpsb2NhbC1ob3N0bmFtZTogdm0wMQo=",
+					"guestinfo.metadata.encoding": "base64",
+					"guestinfo.userdata":          "H4sIAAAAAAAC/+ySvU7FMAyF90h5h+jeMTIXJqRWXfgRExuwu4lbLLVOlThS+/aoSKAu8AKwHVmf/Xk490mUROFlW6hxc52UF8x6mXml2Lo+VYmYt+7kn5J/LZT9Ayr6u695d7LmmWeCN8qFkzTu5uraGmsAftqw5luaUcpAGR4lpMgyNu62Zz0An18prXoJU6oRQpKBx9aFd8yFtKsFsATm3Xg+IubsFoz7zf/0Z9LvvQOw5mMAIflkXu8CAAA=",
+					"guestinfo.userdata.encoding": "gzip+base64",
+				},
+			},
+		},
+		{
+			name: "negative case: too large",
+			guestInfo: GuestInfo{
+				UserData: padded(),
+				MetaData: GuestInfoMetaData{InstanceID: "iid-vmware01"},
+				MaxSize:  64,
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrPayloadTooLarge},
+			},
+		},
+		{
+			name: "negative case: missing instance id",
+			guestInfo: GuestInfo{
+				UserData: padded(),
+			},
+			expected: expected{
+				err: &Error{Op: "render", Err: ErrMissingInstanceID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.guestInfo.Values()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestGuestInfo_Render(t *testing.T) {
+	g := &GuestInfo{
+		MetaData: GuestInfoMetaData{InstanceID: "iid-vmware01", LocalHostname: "vm01"},
+	}
+
+	buf := new(bytes.Buffer)
+	err := g.Render(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "guestinfo.metadata = \"aW5zdGFuY2UtaWQ6IGlpZC12bXdhcmUwMQpsb2NhbC1ob3N0bmFtZTogdm0wMQo=\"\n"+
+		"guestinfo.metadata.encoding = \"base64\"\n", buf.String())
+}
@@ -0,0 +1,659 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ignitionVersion     = "3.3.0"
+	ignitionDefaultUser = "core"
+	ignitionScriptDir   = "/var/lib/gouserdata"
+	ignitionFileMode    = 0o644
+	ignitionScriptMode  = 0o755
+	ignitionSudoersMode = 0o440
+)
+
+type IgnitionConfig struct {
+	Ignition IgnitionMeta    `json:"ignition"`
+	Passwd   IgnitionPasswd  `json:"passwd"`
+	Storage  IgnitionStorage `json:"storage"`
+	Systemd  IgnitionSystemd `json:"systemd"`
+}
+
+type IgnitionMeta struct {
+	Version string `json:"version"`
+}
+
+type IgnitionPasswd struct {
+	Users []IgnitionUser `json:"users,omitempty"`
+}
+
+type IgnitionUser struct {
+	Name              string   `json:"name"`
+	UID               *int     `json:"uid,omitempty"`
+	Gecos             string   `json:"gecos,omitempty"`
+	HomeDir           string   `json:"homeDir,omitempty"`
+	NoCreateHome      bool     `json:"noCreateHome,omitempty"`
+	PrimaryGroup      string   `json:"primaryGroup,omitempty"`
+	Groups            []string `json:"groups,omitempty"`
+	PasswordHash      string   `json:"passwordHash,omitempty"`
+	Shell             string   `json:"shell,omitempty"`
+	System            bool     `json:"system,omitempty"`
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+}
+
+type IgnitionStorage struct {
+	Files []IgnitionFile `json:"files,omitempty"`
+}
+
+type IgnitionFile struct {
+	Path      string             `json:"path"`
+	Overwrite *bool              `json:"overwrite,omitempty"`
+	Mode      *int               `json:"mode,omitempty"`
+	User      *IgnitionNodeOwner `json:"user,omitempty"`
+	Group     *IgnitionNodeOwner `json:"group,omitempty"`
+	Contents  *IgnitionResource  `json:"contents,omitempty"`
+	Append    []IgnitionResource `json:"append,omitempty"`
+}
+
+type IgnitionNodeOwner struct {
+	Name string `json:"name"`
+}
+
+type IgnitionResource struct {
+	Source      string `json:"source"`
+	Compression string `json:"compression,omitempty"`
+}
+
+type IgnitionSystemd struct {
+	Units []IgnitionUnit `json:"units,omitempty"`
+}
+
+type IgnitionUnit struct {
+	Name     string           `json:"name"`
+	Enabled  *bool            `json:"enabled,omitempty"`
+	Mask     bool             `json:"mask,omitempty"`
+	Contents string           `json:"contents,omitempty"`
+	Dropins  []IgnitionDropin `json:"dropins,omitempty"`
+}
+
+type IgnitionDropin struct {
+	Name     string `json:"name"`
+	Contents string `json:"contents,omitempty"`
+}
+
+type IgnitionUntranslated struct {
+	Part      int
+	MediaType MediaType
+	Key       string
+	Reason    string
+}
+
+type IgnitionConverter struct {
+	DefaultUser string
+}
+
+type ignitionConversion struct {
+	config      *IgnitionConfig
+	report      []IgnitionUntranslated
+	defaultUser string
+	part        int
+	mediaType   MediaType
+	lastScript  string
+}
+
+func (c *IgnitionConfig) Render(w io.Writer) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render ignition config", "func", getFuncName(), "error", err)
+		return err
+	}
+
+	if _, err := w.Write(b); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render ignition config", "func", getFuncName(), "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *IgnitionConverter) Convert(m Multipart) (*IgnitionConfig, []IgnitionUntranslated, error) {
+	defaultUser := c.DefaultUser
+	if defaultUser == "" {
+		defaultUser = ignitionDefaultUser
+	}
+
+	conv := &ignitionConversion{
+		config:      &IgnitionConfig{Ignition: IgnitionMeta{Version: ignitionVersion}},
+		report:      make([]IgnitionUntranslated, 0),
+		defaultUser: defaultUser,
+	}
+
+	for i, p := range m.Parts() {
+		conv.part = i
+		conv.mediaType = p.MediaType()
+
+		switch {
+		case p.MediaType() == MediaTypeCloudConfig:
+			if err := conv.cloudConfig(p.Body()); err != nil {
+				logger.Println("failed to convert to ignition", "func", getFuncName(), "part", i, "error", err)
+				return nil, nil, err
+			}
+		case p.MediaType().IsScript():
+			conv.script(p.Body())
+		default:
+			conv.untranslated("", "unsupported media type")
+		}
+	}
+
+	return conv.config, conv.report, nil
+}
+
+func (c *ignitionConversion) untranslated(key string, reason string) {
+	c.report = append(c.report, IgnitionUntranslated{Part: c.part, MediaType: c.mediaType, Key: key, Reason: reason})
+}
+
+func (c *ignitionConversion) cloudConfig(body []byte) error {
+	var doc map[string]any
+	if err := yaml.Unmarshal(body, &doc); err != nil {
+		return &Error{Op: "convert", Err: ErrInvalidBody}
+	}
+
+	keys := maps.Keys(doc)
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		switch key {
+		case "write_files":
+			items, ok := doc[key].([]any)
+			if !ok {
+				c.untranslated(key, "expected a list")
+				continue
+			}
+
+			for i, item := range items {
+				c.writeFile(fmt.Sprintf("%s[%d]", key, i), item)
+			}
+		case "users":
+			items, ok := doc[key].([]any)
+			if !ok {
+				c.untranslated(key, "expected a list")
+				continue
+			}
+
+			for i, item := range items {
+				c.user(fmt.Sprintf("%s[%d]", key, i), item)
+			}
+		case "ssh_authorized_keys":
+			sshKeys, ok := ignitionStrings(doc[key])
+			if !ok {
+				c.untranslated(key, "expected a list of strings")
+				continue
+			}
+
+			u := c.ensureUser(c.defaultUser)
+			u.SSHAuthorizedKeys = append(u.SSHAuthorizedKeys, sshKeys...)
+		case "hostname":
+			hostname, ok := doc[key].(string)
+			if !ok {
+				c.untranslated(key, "expected a string")
+				continue
+			}
+
+			c.addFile(IgnitionFile{
+				Path:      "/etc/hostname",
+				Overwrite: ignitionBool(true),
+				Mode:      ignitionInt(ignitionFileMode),
+				Contents:  ignitionData([]byte(hostname+"\n"), ""),
+			})
+		case "coreos":
+			coreos, ok := doc[key].(map[string]any)
+			if !ok {
+				c.untranslated(key, "expected a mapping")
+				continue
+			}
+
+			subkeys := maps.Keys(coreos)
+			slices.Sort(subkeys)
+			for _, subkey := range subkeys {
+				if subkey != "units" {
+					c.untranslated(key+"."+subkey, "unsupported coreos key")
+					continue
+				}
+
+				units, ok := coreos[subkey].([]any)
+				if !ok {
+					c.untranslated(key+"."+subkey, "expected a list")
+					continue
+				}
+
+				for i, unit := range units {
+					c.unit(fmt.Sprintf("%s.%s[%d]", key, subkey, i), unit)
+				}
+			}
+		default:
+			c.untranslated(key, "unsupported cloud-config key")
+		}
+	}
+
+	return nil
+}
+
+func (c *ignitionConversion) writeFile(key string, v any) {
+	item, ok := v.(map[string]any)
+	if !ok {
+		c.untranslated(key, "expected a mapping")
+		return
+	}
+
+	p, _ := item["path"].(string)
+	if !path.IsAbs(p) {
+		c.untranslated(key, "invalid path")
+		return
+	}
+
+	f := IgnitionFile{Path: p, Mode: ignitionInt(ignitionFileMode)}
+
+	content, _ := item["content"].(string)
+	data := []byte(content)
+	var compression string
+
+	encoding, _ := item["encoding"].(string)
+	switch encoding {
+	case "", "text/plain":
+	case "b64", "base64":
+		b, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			c.untranslated(key+".content", "invalid base64")
+			return
+		}
+		data = b
+	case "gz", "gzip":
+		compression = "gzip"
+	case "gz+b64", "gz+base64", "gzip+b64", "gzip+base64":
+		b, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			c.untranslated(key+".content", "invalid base64")
+			return
+		}
+		data = b
+		compression = "gzip"
+	default:
+		c.untranslated(key+".encoding", "unsupported encoding")
+		return
+	}
+
+	switch perm := item["permissions"].(type) {
+	case nil:
+	case int:
+		f.Mode = ignitionInt(perm)
+	case string:
+		mode, err := strconv.ParseUint(perm, 8, 32)
+		if err != nil {
+			c.untranslated(key+".permissions", "invalid permissions")
+			return
+		}
+		f.Mode = ignitionInt(int(mode))
+	default:
+		c.untranslated(key+".permissions", "invalid permissions")
+		return
+	}
+
+	if owner, ok := item["owner"].(string); ok && owner != "" {
+		user, group, found := strings.Cut(owner, ":")
+		f.User = &IgnitionNodeOwner{Name: user}
+		if found {
+			f.Group = &IgnitionNodeOwner{Name: group}
+		}
+	}
+
+	res := ignitionData(data, compression)
+	if appendContent, _ := item["append"].(bool); appendContent {
+		f.Append = []IgnitionResource{*res}
+	} else {
+		f.Overwrite = ignitionBool(true)
+		f.Contents = res
+	}
+
+	subkeys := maps.Keys(item)
+	slices.Sort(subkeys)
+	for _, subkey := range subkeys {
+		switch subkey {
+		// ignition writes files after creating users, which is what defer asks for
+		case "path", "content", "encoding", "permissions", "owner", "append", "defer":
+		default:
+			c.untranslated(key+"."+subkey, "unsupported write_files key")
+		}
+	}
+
+	c.addFile(f)
+}
+
+func (c *ignitionConversion) user(key string, v any) {
+	switch item := v.(type) {
+	case string:
+		if item == "default" {
+			c.untranslated(key, "distribution default user")
+			return
+		}
+
+		c.ensureUser(item)
+	case map[string]any:
+		name, _ := item["name"].(string)
+		if name == "" {
+			c.untranslated(key, "missing name")
+			return
+		}
+
+		u := c.ensureUser(name)
+
+		subkeys := maps.Keys(item)
+		slices.Sort(subkeys)
+		for _, subkey := range subkeys {
+			val := item[subkey]
+			ok := true
+
+			switch subkey {
+			case "name":
+			case "gecos":
+				u.Gecos, ok = val.(string)
+			case "homedir":
+				u.HomeDir, ok = val.(string)
+			case "shell":
+				u.Shell, ok = val.(string)
+			case "primary_group":
+				u.PrimaryGroup, ok = val.(string)
+			case "passwd", "hashed_passwd":
+				u.PasswordHash, ok = val.(string)
+			case "no_create_home":
+				u.NoCreateHome, ok = val.(bool)
+			case "system":
+				u.System, ok = val.(bool)
+			case "uid":
+				var uid int
+				if uid, ok = val.(int); ok {
+					u.UID = ignitionInt(uid)
+				}
+			case "groups":
+				var groups []string
+				if s, isString := val.(string); isString {
+					for _, g := range strings.Split(s, ",") {
+						if g = strings.TrimSpace(g); g != "" {
+							groups = append(groups, g)
+						}
+					}
+				} else {
+					groups, ok = ignitionStrings(val)
+				}
+				u.Groups = append(u.Groups, groups...)
+			case "ssh_authorized_keys":
+				var sshKeys []string
+				sshKeys, ok = ignitionStrings(val)
+				u.SSHAuthorizedKeys = append(u.SSHAuthorizedKeys, sshKeys...)
+			case "sudo":
+				var rules []string
+				switch val := val.(type) {
+				case bool:
+					ok = !val
+				case string:
+					rules = []string{val}
+				default:
+					rules, ok = ignitionStrings(val)
+				}
+
+				if ok && len(rules) > 0 {
+					c.sudoers(name, rules)
+				}
+			default:
+				c.untranslated(key+"."+subkey, "unsupported users key")
+				continue
+			}
+
+			if !ok {
+				c.untranslated(key+"."+subkey, "invalid value")
+			}
+		}
+	default:
+		c.untranslated(key, "expected a string or mapping")
+	}
+}
+
+func (c *ignitionConversion) sudoers(name string, rules []string) {
+	buf := new(bytes.Buffer)
+	for _, rule := range rules {
+		fmt.Fprintf(buf, "%s %s\n", name, rule)
+	}
+
+	// sudo skips files in sudoers.d whose names contain a dot
+	c.addFile(IgnitionFile{
+		Path:      path.Join("/etc/sudoers.d", "gouserdata-"+strings.ReplaceAll(name, ".", "_")),
+		Overwrite: ignitionBool(true),
+		Mode:      ignitionInt(ignitionSudoersMode),
+		Contents:  ignitionData(buf.Bytes(), ""),
+	})
+}
+
+func (c *ignitionConversion) unit(key string, v any) {
+	item, ok := v.(map[string]any)
+	if !ok {
+		c.untranslated(key, "expected a mapping")
+		return
+	}
+
+	name, _ := item["name"].(string)
+	if name == "" {
+		c.untranslated(key, "missing name")
+		return
+	}
+
+	u := IgnitionUnit{Name: name}
+
+	subkeys := maps.Keys(item)
+	slices.Sort(subkeys)
+	for _, subkey := range subkeys {
+		val := item[subkey]
+		ok := true
+
+		switch subkey {
+		case "name":
+		case "content":
+			u.Contents, ok = val.(string)
+		case "enable":
+			var enable bool
+			if enable, ok = val.(bool); ok && enable {
+				u.Enabled = ignitionBool(true)
+			}
+		case "mask":
+			u.Mask, ok = val.(bool)
+		case "command":
+			// an enabled unit starts on the first boot, which is the closest ignition gets
+			if command, _ := val.(string); command == "start" {
+				u.Enabled = ignitionBool(true)
+			} else {
+				c.untranslated(key+"."+subkey, "unsupported unit command")
+				continue
+			}
+		case "drop-ins", "drop_ins":
+			items, isList := val.([]any)
+			ok = isList
+			for i, item := range items {
+				dropin, _ := item.(map[string]any)
+				dropinName, _ := dropin["name"].(string)
+				if dropinName == "" {
+					c.untranslated(fmt.Sprintf("%s.%s[%d]", key, subkey, i), "missing name")
+					continue
+				}
+
+				contents, _ := dropin["content"].(string)
+				u.Dropins = append(u.Dropins, IgnitionDropin{Name: dropinName, Contents: contents})
+			}
+		default:
+			c.untranslated(key+"."+subkey, "unsupported unit key")
+			continue
+		}
+
+		if !ok {
+			c.untranslated(key+"."+subkey, "invalid value")
+		}
+	}
+
+	c.addUnit(u)
+}
+
+func (c *ignitionConversion) script(body []byte) {
+	if !bytes.HasPrefix(body, []byte("#!")) {
+		c.untranslated("", "missing shebang")
+		return
+	}
+
+	name := fmt.Sprintf("gouserdata-part-%03d", c.part)
+	scriptPath := path.Join(ignitionScriptDir, name)
+	donePath := scriptPath + ".done"
+
+	c.addFile(IgnitionFile{
+		Path:      scriptPath,
+		Overwrite: ignitionBool(true),
+		Mode:      ignitionInt(ignitionScriptMode),
+		Contents:  ignitionData(body, ""),
+	})
+
+	// scripts cloud-init runs once per instance leave a marker so a reboot does not rerun them
+	freq := c.mediaType.Frequency()
+	once := freq != FrequencyAlways && freq != FrequencyPerBoot
+
+	after := "network-online.target"
+	if c.lastScript != "" {
+		after += " " + c.lastScript
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprint(buf, "[Unit]\n")
+	fmt.Fprintf(buf, "Description=gouserdata script part %d\n", c.part)
+	fmt.Fprint(buf, "Wants=network-online.target\n")
+	fmt.Fprintf(buf, "After=%s\n", after)
+	if once {
+		fmt.Fprintf(buf, "ConditionPathExists=!%s\n", donePath)
+	}
+	fmt.Fprint(buf, "\n")
+	fmt.Fprint(buf, "[Service]\n")
+	fmt.Fprint(buf, "Type=oneshot\n")
+	fmt.Fprint(buf, "RemainAfterExit=yes\n")
+	fmt.Fprintf(buf, "ExecStart=%s\n", scriptPath)
+	if once {
+		fmt.Fprintf(buf, "ExecStartPost=/usr/bin/touch %s\n", donePath)
+	}
+	fmt.Fprint(buf, "\n")
+	fmt.Fprint(buf, "[Install]\n")
+	fmt.Fprint(buf, "WantedBy=multi-user.target\n")
+
+	c.addUnit(IgnitionUnit{Name: name + ".service", Enabled: ignitionBool(true), Contents: buf.String()})
+	c.lastScript = name + ".service"
+}
+
+func (c *ignitionConversion) ensureUser(name string) *IgnitionUser {
+	users := c.config.Passwd.Users
+	for i := range users {
+		if users[i].Name == name {
+			return &users[i]
+		}
+	}
+
+	c.config.Passwd.Users = append(users, IgnitionUser{Name: name})
+	return &c.config.Passwd.Users[len(c.config.Passwd.Users)-1]
+}
+
+func (c *ignitionConversion) addFile(f IgnitionFile) {
+	files := c.config.Storage.Files
+	for i := range files {
+		if files[i].Path != f.Path {
+			continue
+		}
+
+		// later parts win, just as cloud-init would overwrite the earlier file
+		if f.Contents == nil {
+			files[i].Append = append(files[i].Append, f.Append...)
+		} else {
+			files[i] = f
+		}
+
+		return
+	}
+
+	c.config.Storage.Files = append(files, f)
+}
+
+func (c *ignitionConversion) addUnit(u IgnitionUnit) {
+	units := c.config.Systemd.Units
+	for i := range units {
+		if units[i].Name == u.Name {
+			units[i] = u
+			return
+		}
+	}
+
+	c.config.Systemd.Units = append(units, u)
+}
+
+func ignitionData(data []byte, compression string) *IgnitionResource {
+	return &IgnitionResource{Source: "data:;base64," + base64.StdEncoding.EncodeToString(data), Compression: compression}
+}
+
+func ignitionStrings(v any) ([]string, bool) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+
+	s := make([]string, 0, len(items))
+	for _, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+
+		s = append(s, str)
+	}
+
+	return s, true
+}
+
+func ignitionBool(b bool) *bool {
+	return &b
+}
+
+func ignitionInt(i int) *int {
+	return &i
+}
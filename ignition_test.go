@@ -0,0 +1,345 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnitionConverter_Convert(t *testing.T) {
+	type expected struct {
+		config *IgnitionConfig
+		report []IgnitionUntranslated
+		err    error
+	}
+
+	tests := []struct {
+		name      string
+		converter IgnitionConverter
+		userData  Multipart
+		expected  expected
+	}{
+		{
+			name:      "positive case: write_files",
+			converter: IgnitionConverter{},
+			userData: mustNewMultipart(
+				mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+
+					"write_files:\n"+
+					"  - path: /etc/motd\n"+
+					"    content: hello\n"+
+					"    permissions: '0600'\n"+
+					"    owner: root:wheel\n"+
+					"  - path: /etc/motd\n"+
+					"    content: aGk=\n"+
+					"    encoding: b64\n"+
+					"    append: true\n"+
+					"  - path: /etc/app.conf\n"+
+					"    content: H4sIAAAAAAAA/0vLz+cCAKhlMn4EAAAA\n"+
+					"    encoding: gzip+base64\n"+
+					"    defer: true\n"+
+					"  - path: relative\n"+
+					"    content: foo\n"+
+					"  - path: /etc/zstd\n"+
+					"    encoding: zstd\n"+
+					"  - path: /etc/source\n"+
+					"    source:\n"+
+					"      uri: https://example.com/source\n")),
+			),
+			expected: expected{
+				config: &IgnitionConfig{
+					Ignition: IgnitionMeta{Version: "3.3.0"},
+					Storage: IgnitionStorage{
+						Files: []IgnitionFile{
+							{
+								Path:      "/etc/motd",
+								Overwrite: ignitionBool(true),
+								Mode:      ignitionInt(0o600),
+								User:      &IgnitionNodeOwner{Name: "root"},
+								Group:     &IgnitionNodeOwner{Name: "wheel"},
+								Contents:  &IgnitionResource{Source: "data:;base64,aGVsbG8="},
+								Append:    []IgnitionResource{{Source: "data:;base64,aGk="}},
+							},
+							{
+								Path:      "/etc/app.conf",
+								Overwrite: ignitionBool(true),
+								Mode:      ignitionInt(0o644),
+								Contents:  &IgnitionResource{Source: "data:;base64,H4sIAAAAAAAA/0vLz+cCAKhlMn4EAAAA", Compression: "gzip"},
+							},
+							{
+								Path:      "/etc/source",
+								Overwrite: ignitionBool(true),
+								Mode:      ignitionInt(0o644),
+								Contents:  &IgnitionResource{Source: "data:;base64,"},
+							},
+						},
+					},
+				},
+				report: []IgnitionUntranslated{
+					{Part: 0, MediaType: MediaTypeCloudConfig, Key: "write_files[3]", Reason: "invalid path"},
+					{Part: 0, MediaType: MediaTypeCloudConfig, Key: "write_files[4].encoding", Reason: "unsupported encoding"},
+					{Part: 0, MediaType: MediaTypeCloudConfig, Key: "write_files[5].source", Reason: "unsupported write_files key"},
+				},
+			},
+		},
+		{
+			name:      "positive case: users",
+			converter: IgnitionConverter{DefaultUser: "admin"},
+			userData: mustNewMultipart(
+				mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+
+					"ssh_authorized_keys:\n"+
+					"  - ssh-ed25519 AAAA\n"+
+					"users:\n"+
+					"  - default\n"+
+					"  - bob\n"+
+					"  - name: ops\n"+
+					"    uid: 1001\n"+
+					"    groups: wheel, docker\n"+
+					"    shell: /bin/bash\n"+
+					"    sudo: ALL=(ALL) NOPASSWD:ALL\n"+
+					"    lock_passwd: true\n"+
+					"    ssh_authorized_keys:\n"+
+					"      - ssh-ed25519 BBBB\n")),
+				mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+
+					"users:\n"+
+					"  - name: ops\n"+
+					"    groups: [adm]\n"+
+					"    uid: ops\n")),
+			),
+			expected: expected{
+				config: &IgnitionConfig{
+					Ignition: IgnitionMeta{Version: "3.3.0"},
+					Passwd: IgnitionPasswd{
+						Users: []IgnitionUser{
+							{Name: "admin", SSHAuthorizedKeys: []string{"ssh-ed25519 AAAA"}},
+							{Name: "bob"},
+							{
+								Name:              "ops",
+								UID:               ignitionInt(1001),
+								Groups:            []string{"wheel", "docker", "adm"},
+								Shell:             "/bin/bash",
+								SSHAuthorizedKeys: []string{"ssh-ed25519 BBBB"},
+							},
+						},
+					},
+					Storage: IgnitionStorage{
+						Files: []IgnitionFile{
+							{
+								Path:      "/etc/sudoers.d/gouserdata-ops",
+								Overwrite: ignitionBool(true),
+								Mode:      ignitionInt(0o440),
+								Contents:  &IgnitionResource{Source: "data:;base64,b3BzIEFMTD0oQUxMKSBOT1BBU1NXRDpBTEwK"},
+							},
+						},
+					},
+				},
+				report: []IgnitionUntranslated{
+					{Part: 0, MediaType: MediaTypeCloudConfig, Key: "users[0]", Reason: "distribution default user"},
+					{Part: 0, MediaType: MediaTypeCloudConfig, Key: "users[2].lock_passwd", Reason: "unsupported users key"},
+					{Part: 1, MediaType: MediaTypeCloudConfig, Key: "users[0].uid", Reason: "invalid value"},
+				},
+			},
+		},
+		{
+			name:      "positive case: units and scripts",
+			converter: IgnitionConverter{},
+			userData: mustNewMultipart(
+				mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+
+					"hostname: node-0\n"+
+					"runcmd:\n"+
+					"  - ls\n"+
+					"coreos:\n"+
+					"  units:\n"+
+					"    - name: docker.service\n"+
+					"      command: start\n"+
+					"      drop-ins:\n"+
+					"        - name: 10-opts.conf\n"+
+					"          content: |\n"+
+					"            [Service]\n"+
+					"    - name: etcd.service\n"+
+					"      mask: true\n"+
+					"      command: stop\n"+
+					"  update:\n"+
+					"    reboot-strategy: off\n")),
+				mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/sh\necho always\n")),
+				mustNewPart(MediaTypeXShellscriptPerInstance, []byte("#!/bin/sh\necho once\n")),
+				mustNewPart(MediaTypeCloudBoothook, []byte("#cloud-boothook\necho boothook\n")),
+			),
+			expected: expected{
+				config: &IgnitionConfig{
+					Ignition: IgnitionMeta{Version: "3.3.0"},
+					Storage: IgnitionStorage{
+						Files: []IgnitionFile{
+							{
+								Path:      "/etc/hostname",
+								Overwrite: ignitionBool(true),
+								Mode:      ignitionInt(0o644),
+								Contents:  &IgnitionResource{Source: "data:;base64,bm9kZS0wCg=="},
+							},
+							{
+								Path:      "/var/lib/gouserdata/gouserdata-part-001",
+								Overwrite: ignitionBool(true),
+								Mode:      ignitionInt(0o755),
+								Contents:  &IgnitionResource{Source: "data:;base64,IyEvYmluL3NoCmVjaG8gYWx3YXlzCg=="},
+							},
+							{
+								Path:      "/var/lib/gouserdata/gouserdata-part-002",
+								Overwrite: ignitionBool(true),
+								Mode:      ignitionInt(0o755),
+								Contents:  &IgnitionResource{Source: "data:;base64,IyEvYmluL3NoCmVjaG8gb25jZQo="},
+							},
+						},
+					},
+					Systemd: IgnitionSystemd{
+						Units: []IgnitionUnit{
+							{
+								Name:    "docker.service",
+								Enabled: ignitionBool(true),
+								Dropins: []IgnitionDropin{{Name: "10-opts.conf", Contents: "[Service]\n"}},
+							},
+							{
+								Name: "etcd.service",
+								Mask: true,
+							},
+							{
+								Name:    "gouserdata-part-001.service",
+								Enabled: ignitionBool(true),
+								Contents: "[Unit]\n" +
+									"Description=gouserdata script part 1\n" +
+									"Wants=network-online.target\n" +
+									"After=network-online.target\n" +
+									"\n" +
+									"[Service]\n" +
+									"Type=oneshot\n" +
+									"RemainAfterExit=yes\n" +
+									"ExecStart=/var/lib/gouserdata/gouserdata-part-001\n" +
+									"\n" +
+									"[Install]\n" +
+									"WantedBy=multi-user.target\n",
+							},
+							{
+								Name:    "gouserdata-part-002.service",
+								Enabled: ignitionBool(true),
+								Contents: "[Unit]\n" +
+									"Description=gouserdata script part 2\n" +
+									"Wants=network-online.target\n" +
+									"After=network-online.target gouserdata-part-001.service\n" +
+									"ConditionPathExists=!/var/lib/gouserdata/gouserdata-part-002.done\n" +
+									"\n" +
+									"[Service]\n" +
+									"Type=oneshot\n" +
+									"RemainAfterExit=yes\n" +
+									"ExecStart=/var/lib/gouserdata/gouserdata-part-002\n" +
+									"ExecStartPost=/usr/bin/touch /var/lib/gouserdata/gouserdata-part-002.done\n" +
+									"\n" +
+									"[Install]\n" +
+									"WantedBy=multi-user.target\n",
+							},
+						},
+					},
+				},
+				report: []IgnitionUntranslated{
+					{Part: 0, MediaType: MediaTypeCloudConfig, Key: "coreos.units[1].command", Reason: "unsupported unit command"},
+					{Part: 0, MediaType: MediaTypeCloudConfig, Key: "coreos.update", Reason: "unsupported coreos key"},
+					{Part: 0, MediaType: MediaTypeCloudConfig, Key: "runcmd", Reason: "unsupported cloud-config key"},
+					{Part: 3, MediaType: MediaTypeCloudBoothook, Key: "", Reason: "unsupported media type"},
+				},
+			},
+		},
+		{
+			name:      "positive case: empty",
+			converter: IgnitionConverter{},
+			userData:  mustNewMultipart(),
+			expected: expected{
+				config: &IgnitionConfig{Ignition: IgnitionMeta{Version: "3.3.0"}},
+				report: []IgnitionUntranslated{},
+			},
+		},
+		{
+			name:      "negative case: invalid cloud-config",
+			converter: IgnitionConverter{},
+			userData: mustNewMultipart(
+				mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\nusers: [\n")),
+			),
+			expected: expected{
+				err: &Error{Op: "convert", Err: ErrInvalidBody},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, report, err := tt.converter.Convert(tt.userData)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.config, config)
+				assert.Equal(t, tt.expected.report, report)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestIgnitionConfig_Render(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		config   IgnitionConfig
+		expected expected
+	}{
+		{
+			name: "positive case",
+			config: IgnitionConfig{
+				Ignition: IgnitionMeta{Version: "3.3.0"},
+				Passwd:   IgnitionPasswd{Users: []IgnitionUser{{Name: "core", SSHAuthorizedKeys: []string{"ssh-ed25519 AAAA"}}}},
+				Systemd:  IgnitionSystemd{Units: []IgnitionUnit{{Name: "docker.service", Enabled: ignitionBool(true)}}},
+			},
+			expected: expected{
+				res: `{"ignition":{"version":"3.3.0"},` +
+					`"passwd":{"users":[{"name":"core","sshAuthorizedKeys":["ssh-ed25519 AAAA"]}]},` +
+					`"storage":{},` +
+					`"systemd":{"units":[{"name":"docker.service","enabled":true}]}}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			err := tt.config.Render(w)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, w.String())
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
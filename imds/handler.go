@@ -0,0 +1,415 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package imds
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	userdata "github.com/Aton-Kish/gouserdata"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	tokenPath       = "/latest/api/token"
+	tokenHeader     = "X-aws-ec2-metadata-token"
+	tokenTTLHeader  = "X-aws-ec2-metadata-token-ttl-seconds"
+	hopsHeader      = "X-Gouserdata-Hops"
+	maxTokenTTL     = 21600
+	defaultHopLimit = 1
+	maxHopLimit     = 64
+	tokenSize       = 32
+)
+
+var (
+	versionRe = regexp.MustCompile(`^(latest|[0-9]{4}-[0-9]{2}-[0-9]{2})$`)
+)
+
+type MetaData struct {
+	AMIID            string
+	InstanceID       string
+	InstanceType     string
+	Hostname         string
+	LocalHostname    string
+	LocalIPv4        string
+	PublicHostname   string
+	PublicIPv4       string
+	MAC              string
+	AvailabilityZone string
+	Region           string
+	PublicKeys       map[string]string
+}
+
+type Handler interface {
+	SetMetaData(path string, value string) error
+	SetTokenRequired(required bool)
+	SetHopLimit(limit int) error
+	SetClock(now func() time.Time)
+	http.Handler
+}
+
+type handler struct {
+	mu            sync.Mutex
+	userData      userdata.Renderer
+	entries       map[string]string
+	labels        map[string]string
+	tokens        map[string]time.Time
+	tokenRequired bool
+	hopLimit      int
+	now           func() time.Time
+}
+
+func NewHandler(userData userdata.Renderer, metaData MetaData) Handler {
+	h := &handler{
+		userData: userData,
+		entries:  make(map[string]string),
+		labels:   make(map[string]string),
+		tokens:   make(map[string]time.Time),
+		hopLimit: defaultHopLimit,
+		now:      time.Now,
+	}
+
+	for path, value := range map[string]string{
+		"ami-id":                      metaData.AMIID,
+		"instance-id":                 metaData.InstanceID,
+		"instance-type":               metaData.InstanceType,
+		"hostname":                    metaData.Hostname,
+		"local-hostname":              metaData.LocalHostname,
+		"local-ipv4":                  metaData.LocalIPv4,
+		"public-hostname":             metaData.PublicHostname,
+		"public-ipv4":                 metaData.PublicIPv4,
+		"mac":                         metaData.MAC,
+		"placement/availability-zone": metaData.AvailabilityZone,
+		"placement/region":            metaData.Region,
+	} {
+		if value != "" {
+			h.entries[path] = value
+		}
+	}
+
+	if metaData.MAC != "" {
+		prefix := "network/interfaces/macs/" + metaData.MAC + "/"
+		h.entries[prefix+"mac"] = metaData.MAC
+		if metaData.LocalIPv4 != "" {
+			h.entries[prefix+"local-ipv4s"] = metaData.LocalIPv4
+		}
+		if metaData.PublicIPv4 != "" {
+			h.entries[prefix+"public-ipv4s"] = metaData.PublicIPv4
+		}
+	}
+
+	names := maps.Keys(metaData.PublicKeys)
+	slices.Sort(names)
+	for i, name := range names {
+		h.entries[fmt.Sprintf("public-keys/%d/openssh-key", i)] = metaData.PublicKeys[name]
+		h.labels[fmt.Sprintf("public-keys/%d", i)] = fmt.Sprintf("%d=%s", i, name)
+	}
+
+	return h
+}
+
+func (h *handler) SetMetaData(path string, value string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.validPath(path) {
+		err := &Error{Op: "configure", Err: ErrInvalidPath}
+		logger.Println("failed to set meta-data", "func", getFuncName(), "path", path, "error", err)
+		return err
+	}
+
+	h.entries[path] = value
+
+	return nil
+}
+
+func (h *handler) SetTokenRequired(required bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.tokenRequired = required
+}
+
+func (h *handler) SetHopLimit(limit int) error {
+	if limit < 1 || limit > maxHopLimit {
+		err := &Error{Op: "configure", Err: ErrInvalidHopLimit}
+		logger.Println("failed to set hop limit", "func", getFuncName(), "limit", limit, "error", err)
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.hopLimit = limit
+
+	return nil
+}
+
+func (h *handler) SetClock(now func() time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if now == nil {
+		now = time.Now
+	}
+
+	h.now = now
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == tokenPath {
+		h.serveToken(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		logger.Println("rejected request", "func", getFuncName(), "method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr)
+		return
+	}
+
+	if !h.authorize(r.Header.Get(tokenHeader)) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		logger.Println("unauthorized request", "func", getFuncName(), "path", r.URL.Path, "remote", r.RemoteAddr)
+		return
+	}
+
+	data, ok, err := h.lookup(r.URL.Path)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		logger.Println("failed to serve", "func", getFuncName(), "path", r.URL.Path, "remote", r.RemoteAddr, "error", err)
+		return
+	}
+
+	if !ok {
+		http.NotFound(w, r)
+		logger.Println("not found", "func", getFuncName(), "path", r.URL.Path, "remote", r.RemoteAddr)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(data)
+	}
+
+	logger.Println("served", "func", getFuncName(), "path", r.URL.Path, "remote", r.RemoteAddr)
+}
+
+func (h *handler) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.Header().Set("Allow", http.MethodPut)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		logger.Println("rejected token request", "func", getFuncName(), "method", r.Method, "remote", r.RemoteAddr)
+		return
+	}
+
+	// IMDS refuses tokens requested through a proxy
+	if r.Header.Get("X-Forwarded-For") != "" {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		logger.Println("forbidden token request", "func", getFuncName(), "remote", r.RemoteAddr)
+		return
+	}
+
+	ttl, err := strconv.Atoi(r.Header.Get(tokenTTLHeader))
+	if err != nil || ttl < 1 || ttl > maxTokenTTL {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		logger.Println("invalid token ttl", "func", getFuncName(), "ttl", r.Header.Get(tokenTTLHeader), "remote", r.RemoteAddr)
+		return
+	}
+
+	// the header stands in for the network path, a missing header means the client sits on the instance
+	hops := 1
+	if v := r.Header.Get(hopsHeader); v != "" {
+		hops, err = strconv.Atoi(v)
+		if err != nil || hops < 1 {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			logger.Println("invalid hops", "func", getFuncName(), "hops", v, "remote", r.RemoteAddr)
+			return
+		}
+	}
+
+	h.mu.Lock()
+	limit := h.hopLimit
+	h.mu.Unlock()
+
+	// the response packet would be dropped once its ttl runs out, so the client never hears back
+	if hops > limit {
+		logger.Println("token response exceeded hop limit", "func", getFuncName(), "hops", hops, "limit", limit, "remote", r.RemoteAddr)
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				_ = conn.Close()
+			}
+		}
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	b := make([]byte, tokenSize)
+	if _, err := rand.Read(b); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		logger.Println("failed to issue token", "func", getFuncName(), "remote", r.RemoteAddr, "error", err)
+		return
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	h.tokens[token] = h.now().Add(time.Duration(ttl) * time.Second)
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set(tokenTTLHeader, strconv.Itoa(ttl))
+	_, _ = w.Write([]byte(token))
+
+	logger.Println("issued token", "func", getFuncName(), "ttl", ttl, "remote", r.RemoteAddr)
+}
+
+func (h *handler) authorize(token string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	for t, expiry := range h.tokens {
+		if !now.Before(expiry) {
+			delete(h.tokens, t)
+		}
+	}
+
+	if token == "" {
+		return !h.tokenRequired
+	}
+
+	_, ok := h.tokens[token]
+
+	return ok
+}
+
+func (h *handler) lookup(urlPath string) ([]byte, bool, error) {
+	elems := strings.SplitN(strings.Trim(urlPath, "/"), "/", 3)
+	if elems[0] == "" {
+		return []byte("latest"), true, nil
+	}
+
+	if !versionRe.MatchString(elems[0]) {
+		return nil, false, nil
+	}
+
+	if len(elems) == 1 {
+		if h.userData == nil {
+			return []byte("meta-data"), true, nil
+		}
+
+		return []byte("meta-data\nuser-data"), true, nil
+	}
+
+	switch elems[1] {
+	case "user-data":
+		if h.userData == nil || len(elems) > 2 {
+			return nil, false, nil
+		}
+
+		buf := new(bytes.Buffer)
+		if err := h.userData.Render(buf); err != nil {
+			return nil, false, err
+		}
+
+		return buf.Bytes(), true, nil
+	case "meta-data":
+		var path string
+		if len(elems) > 2 {
+			path = elems[2]
+		}
+
+		return h.metaData(path)
+	default:
+		return nil, false, nil
+	}
+}
+
+func (h *handler) metaData(path string) ([]byte, bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if value, ok := h.entries[path]; ok {
+		return []byte(value), true, nil
+	}
+
+	prefix := ""
+	if path != "" {
+		prefix = path + "/"
+	}
+
+	children := make(map[string]struct{})
+	for key := range h.entries {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		child, rest, dir := strings.Cut(strings.TrimPrefix(key, prefix), "/")
+		if label, ok := h.labels[prefix+child]; ok {
+			children[label] = struct{}{}
+		} else if dir && rest != "" {
+			children[child+"/"] = struct{}{}
+		} else {
+			children[child] = struct{}{}
+		}
+	}
+
+	if len(children) == 0 {
+		return nil, false, nil
+	}
+
+	listing := maps.Keys(children)
+	slices.Sort(listing)
+
+	return []byte(strings.Join(listing, "\n")), true, nil
+}
+
+func (h *handler) validPath(path string) bool {
+	if path == "" {
+		return false
+	}
+
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return false
+		}
+	}
+
+	for key := range h.entries {
+		// a path cannot be both a leaf and a directory
+		if strings.HasPrefix(key, path+"/") || strings.HasPrefix(path, key+"/") {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,516 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package imds
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	userdata "github.com/Aton-Kish/gouserdata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	type args struct {
+		tokenRequired bool
+		method        string
+		path          string
+		header        http.Header
+	}
+
+	type expected struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: root",
+			args: args{
+				method: http.MethodGet,
+				path:   "/",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "latest",
+			},
+		},
+		{
+			name: "positive case: version",
+			args: args{
+				method: http.MethodGet,
+				path:   "/2009-04-04/",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "meta-data\n" + "user-data",
+			},
+		},
+		{
+			name: "positive case: user-data",
+			args: args{
+				method: http.MethodGet,
+				path:   "/latest/user-data",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+					"Mime-Version: 1.0\r\n" +
+					"\r\n" +
+					"--+Go+User+Data+Boundary==\r\n" +
+					"Content-Transfer-Encoding: 7bit\r\n" +
+					"Content-Type: text/x-shellscript; charset=us-ascii\r\n" +
+					"\r\n" +
+					"#!/bin/sh\n" +
+					"echo hello\r\n" +
+					"\r\n" +
+					"--+Go+User+Data+Boundary==--\r\n",
+			},
+		},
+		{
+			name: "positive case: meta-data listing",
+			args: args{
+				method: http.MethodGet,
+				path:   "/latest/meta-data/",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "ami-id\n" +
+					"instance-id\n" +
+					"local-ipv4\n" +
+					"mac\n" +
+					"network/\n" +
+					"placement/\n" +
+					"public-keys/\n" +
+					"tags/",
+			},
+		},
+		{
+			name: "positive case: meta-data leaf",
+			args: args{
+				method: http.MethodGet,
+				path:   "/latest/meta-data/placement/availability-zone",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "ap-northeast-1a",
+			},
+		},
+		{
+			name: "positive case: public-keys listing",
+			args: args{
+				method: http.MethodGet,
+				path:   "/latest/meta-data/public-keys/",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "0=admin\n" + "1=deploy",
+			},
+		},
+		{
+			name: "positive case: public key",
+			args: args{
+				method: http.MethodGet,
+				path:   "/latest/meta-data/public-keys/1/openssh-key",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "ssh-ed25519 AAAA deploy",
+			},
+		},
+		{
+			name: "positive case: interface",
+			args: args{
+				method: http.MethodGet,
+				path:   "/latest/meta-data/network/interfaces/macs/0e:00:00:00:00:01/local-ipv4s",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "10.0.0.10",
+			},
+		},
+		{
+			name: "positive case: custom meta-data",
+			args: args{
+				method: http.MethodGet,
+				path:   "/latest/meta-data/tags/instance/Name",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "web-1",
+			},
+		},
+		{
+			name: "negative case: not found",
+			args: args{
+				method: http.MethodGet,
+				path:   "/latest/meta-data/public-ipv4",
+			},
+			expected: expected{
+				code: http.StatusNotFound,
+				body: "404 page not found\n",
+			},
+		},
+		{
+			name: "negative case: method not allowed",
+			args: args{
+				method: http.MethodPost,
+				path:   "/latest/user-data",
+			},
+			expected: expected{
+				code: http.StatusMethodNotAllowed,
+				body: "Method Not Allowed\n",
+			},
+		},
+		{
+			name: "negative case: token required",
+			args: args{
+				tokenRequired: true,
+				method:        http.MethodGet,
+				path:          "/latest/user-data",
+			},
+			expected: expected{
+				code: http.StatusUnauthorized,
+				body: "Unauthorized\n",
+			},
+		},
+		{
+			name: "negative case: invalid token",
+			args: args{
+				method: http.MethodGet,
+				path:   "/latest/user-data",
+				header: http.Header{tokenHeader: {"invalid"}},
+			},
+			expected: expected{
+				code: http.StatusUnauthorized,
+				body: "Unauthorized\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t)
+			h.SetTokenRequired(tt.args.tokenRequired)
+
+			req := httptest.NewRequest(tt.args.method, tt.args.path, nil)
+			for k, v := range tt.args.header {
+				req.Header[http.CanonicalHeaderKey(k)] = v
+			}
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expected.code, rec.Code)
+			assert.Equal(t, tt.expected.body, rec.Body.String())
+		})
+	}
+}
+
+func TestHandler_token(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	h := newTestHandler(t)
+	h.SetTokenRequired(true)
+	h.SetClock(func() time.Time { return now })
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	res, body := doRequest(t, srv, http.MethodPut, tokenPath, http.Header{tokenTTLHeader: {"60"}})
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "60", res.Header.Get(tokenTTLHeader))
+	token := body
+
+	res, body = doRequest(t, srv, http.MethodGet, "/latest/meta-data/instance-id", http.Header{tokenHeader: {token}})
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "i-0123456789abcdef0", body)
+
+	now = now.Add(60 * time.Second)
+
+	res, _ = doRequest(t, srv, http.MethodGet, "/latest/meta-data/instance-id", http.Header{tokenHeader: {token}})
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestHandler_tokenRequest(t *testing.T) {
+	type args struct {
+		method string
+		header http.Header
+	}
+
+	type expected struct {
+		code int
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case",
+			args: args{
+				method: http.MethodPut,
+				header: http.Header{tokenTTLHeader: {"21600"}},
+			},
+			expected: expected{
+				code: http.StatusOK,
+			},
+		},
+		{
+			name: "negative case: missing ttl",
+			args: args{
+				method: http.MethodPut,
+			},
+			expected: expected{
+				code: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "negative case: ttl out of range",
+			args: args{
+				method: http.MethodPut,
+				header: http.Header{tokenTTLHeader: {"21601"}},
+			},
+			expected: expected{
+				code: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "positive case: hops within limit",
+			args: args{
+				method: http.MethodPut,
+				header: http.Header{tokenTTLHeader: {"60"}, hopsHeader: {"1"}},
+			},
+			expected: expected{
+				code: http.StatusOK,
+			},
+		},
+		{
+			name: "negative case: invalid hops",
+			args: args{
+				method: http.MethodPut,
+				header: http.Header{tokenTTLHeader: {"60"}, hopsHeader: {"0"}},
+			},
+			expected: expected{
+				code: http.StatusBadRequest,
+			},
+		},
+		{
+			name: "negative case: forwarded",
+			args: args{
+				method: http.MethodPut,
+				header: http.Header{tokenTTLHeader: {"60"}, "X-Forwarded-For": {"192.0.2.1"}},
+			},
+			expected: expected{
+				code: http.StatusForbidden,
+			},
+		},
+		{
+			name: "negative case: method not allowed",
+			args: args{
+				method: http.MethodGet,
+				header: http.Header{tokenTTLHeader: {"60"}},
+			},
+			expected: expected{
+				code: http.StatusMethodNotAllowed,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t)
+
+			req := httptest.NewRequest(tt.args.method, tokenPath, nil)
+			for k, v := range tt.args.header {
+				req.Header[http.CanonicalHeaderKey(k)] = v
+			}
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expected.code, rec.Code)
+		})
+	}
+}
+
+func TestHandler_hopLimit(t *testing.T) {
+	h := newTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPut, tokenPath, nil)
+	req.Header.Set(tokenTTLHeader, "60")
+	req.Header.Set(hopsHeader, "2")
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	assert.False(t, rec.Flushed)
+	assert.Empty(t, rec.Body.String())
+	assert.Empty(t, rec.Header().Get(tokenTTLHeader))
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequest(http.MethodPut, srv.URL+tokenPath, nil)
+	assert.NoError(t, err)
+	req.Header.Set(tokenTTLHeader, "60")
+	req.Header.Set(hopsHeader, "2")
+
+	_, err = srv.Client().Do(req)
+	assert.Error(t, err)
+
+	res, _ := doRequest(t, srv, http.MethodPut, tokenPath, http.Header{tokenTTLHeader: {"60"}})
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	assert.NoError(t, h.SetHopLimit(2))
+
+	res, _ = doRequest(t, srv, http.MethodPut, tokenPath, http.Header{tokenTTLHeader: {"60"}, hopsHeader: {"2"}})
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestHandler_SetHopLimit(t *testing.T) {
+	h := NewHandler(nil, MetaData{})
+
+	assert.NoError(t, h.SetHopLimit(64))
+	assert.Equal(t, &Error{Op: "configure", Err: ErrInvalidHopLimit}, h.SetHopLimit(0))
+	assert.Equal(t, &Error{Op: "configure", Err: ErrInvalidHopLimit}, h.SetHopLimit(65))
+}
+
+func TestHandler_SetMetaData(t *testing.T) {
+	type args struct {
+		path string
+	}
+
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case",
+			args: args{
+				path: "tags/instance/Name",
+			},
+			expected: expected{},
+		},
+		{
+			name: "negative case: empty element",
+			args: args{
+				path: "tags//Name",
+			},
+			expected: expected{
+				err: &Error{Op: "configure", Err: ErrInvalidPath},
+			},
+		},
+		{
+			name: "negative case: leaf used as directory",
+			args: args{
+				path: "instance-id/value",
+			},
+			expected: expected{
+				err: &Error{Op: "configure", Err: ErrInvalidPath},
+			},
+		},
+		{
+			name: "negative case: directory used as leaf",
+			args: args{
+				path: "placement",
+			},
+			expected: expected{
+				err: &Error{Op: "configure", Err: ErrInvalidPath},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(nil, MetaData{InstanceID: "i-0123456789abcdef0", Region: "ap-northeast-1"})
+
+			err := h.SetMetaData(tt.args.path, "value")
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func newTestHandler(t *testing.T) Handler {
+	t.Helper()
+
+	m, err := userdata.NewMultipart()
+	assert.NoError(t, err)
+
+	p, err := userdata.NewPart(userdata.MediaTypeXShellscript, []byte("#!/bin/sh\n"+"echo hello"))
+	assert.NoError(t, err)
+	m.Append(p)
+
+	h := NewHandler(m, MetaData{
+		AMIID:            "ami-0123456789abcdef0",
+		InstanceID:       "i-0123456789abcdef0",
+		LocalIPv4:        "10.0.0.10",
+		MAC:              "0e:00:00:00:00:01",
+		AvailabilityZone: "ap-northeast-1a",
+		Region:           "ap-northeast-1",
+		PublicKeys: map[string]string{
+			"deploy": "ssh-ed25519 AAAA deploy",
+			"admin":  "ssh-ed25519 AAAA admin",
+		},
+	})
+	assert.NoError(t, h.SetMetaData("tags/instance/Name", "web-1"))
+
+	return h
+}
+
+func doRequest(t *testing.T, srv *httptest.Server, method string, path string, header http.Header) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, srv.URL+path, nil)
+	assert.NoError(t, err)
+	for k, v := range header {
+		req.Header[http.CanonicalHeaderKey(k)] = v
+	}
+
+	res, err := srv.Client().Do(req)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+
+	return res, strings.TrimSpace(string(b))
+}
@@ -0,0 +1,513 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	isoSectorSize   = 2048
+	isoSystemArea   = 16
+	isoMaxVolumeID  = 32
+	isoMaxJolietLen = 64
+	// a directory record is at most 255 bytes, and the padded 8.3 identifier (48), PX (36)
+	// and the NM header (5) leave the rest, less one byte of padding, for the Rock Ridge name
+	isoMaxRockRidgeLen = 255 - 48 - 36 - 5 - 1
+
+	rripIdentifier = "RRIP_1991A"
+	rripDescriptor = "THE ROCK RIDGE INTERCHANGE PROTOCOL PROVIDES SUPPORT FOR POSIX FILE SYSTEM SEMANTICS"
+)
+
+type ISO9660Image interface {
+	FileSink
+	Renderer
+}
+
+type isoImage struct {
+	volumeID string
+	root     *fileNode
+}
+
+type isoNode struct {
+	*fileNode
+	parent     *isoNode
+	children   []*isoNode
+	number     int
+	isoName    string
+	jolietName string
+	location   uint32
+	size       uint32
+	jLocation  uint32
+	jSize      uint32
+}
+
+func NewISO9660Image(volumeID string) (ISO9660Image, error) {
+	if volumeID == "" || len(volumeID) > isoMaxVolumeID || strings.IndexFunc(volumeID, isNotPrintableASCII) >= 0 {
+		err := &Error{Op: "initialize", Err: ErrInvalidVolumeID}
+		logger.Println("failed to initialize iso9660 image", "func", getFuncName(), "volumeID", volumeID, "error", err)
+		return nil, err
+	}
+
+	return &isoImage{volumeID: volumeID, root: newFileTree()}, nil
+}
+
+func (img *isoImage) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	for _, elem := range strings.Split(name, "/") {
+		if len(utf16.Encode([]rune(elem))) > isoMaxJolietLen || len(elem) > isoMaxRockRidgeLen {
+			err := &Error{Op: "write", Err: ErrInvalidPath}
+			logger.Println("failed to write iso9660 file", "func", getFuncName(), "name", name, "error", err)
+			return err
+		}
+	}
+
+	if err := img.root.writeFile(name, data, perm); err != nil {
+		err = &Error{Op: "write", Err: err}
+		logger.Println("failed to write iso9660 file", "func", getFuncName(), "name", name, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (img *isoImage) Render(w io.Writer) error {
+	b := img.build()
+
+	if _, err := w.Write(b); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render iso9660 image", "func", getFuncName(), "volumeID", img.volumeID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (img *isoImage) build() []byte {
+	dirs := img.directories()
+	for _, dir := range dirs {
+		assignISONames(dir)
+	}
+
+	// system area, primary, joliet and terminator volume descriptors
+	sector := uint32(isoSystemArea + 3)
+
+	pathTable := isoPathTable(dirs, false, binary.LittleEndian)
+	pathTableSectors := sectorsOf(len(pathTable))
+	lPath := sector
+	mPath := lPath + pathTableSectors
+	jPathTable := isoPathTable(dirs, true, binary.LittleEndian)
+	jPathTableSectors := sectorsOf(len(jPathTable))
+	jlPath := mPath + pathTableSectors
+	jmPath := jlPath + jPathTableSectors
+	sector = jmPath + jPathTableSectors
+
+	for _, dir := range dirs {
+		dir.size = uint32(isoDirectorySize(dir, false))
+		dir.location = sector
+		sector += sectorsOf(int(dir.size))
+	}
+
+	for _, dir := range dirs {
+		dir.jSize = uint32(isoDirectorySize(dir, true))
+		dir.jLocation = sector
+		sector += sectorsOf(int(dir.jSize))
+	}
+
+	for _, dir := range dirs {
+		for _, child := range dir.children {
+			if child.mode.IsDir() {
+				continue
+			}
+
+			child.size = uint32(len(child.data))
+			child.jSize = child.size
+			if child.size == 0 {
+				continue
+			}
+
+			child.location = sector
+			child.jLocation = sector
+			sector += sectorsOf(len(child.data))
+		}
+	}
+
+	total := sector
+	out := make([]byte, int(total)*isoSectorSize)
+
+	copy(out[(isoSystemArea)*isoSectorSize:], img.volumeDescriptor(false, total, len(pathTable), lPath, mPath, dirs[0]))
+	copy(out[(isoSystemArea+1)*isoSectorSize:], img.volumeDescriptor(true, total, len(jPathTable), jlPath, jmPath, dirs[0]))
+	copy(out[(isoSystemArea+2)*isoSectorSize:], isoTerminator())
+
+	copy(out[int(lPath)*isoSectorSize:], isoPathTable(dirs, false, binary.LittleEndian))
+	copy(out[int(mPath)*isoSectorSize:], isoPathTable(dirs, false, binary.BigEndian))
+	copy(out[int(jlPath)*isoSectorSize:], isoPathTable(dirs, true, binary.LittleEndian))
+	copy(out[int(jmPath)*isoSectorSize:], isoPathTable(dirs, true, binary.BigEndian))
+
+	for _, dir := range dirs {
+		copy(out[int(dir.location)*isoSectorSize:], isoDirectory(dir, false))
+		copy(out[int(dir.jLocation)*isoSectorSize:], isoDirectory(dir, true))
+
+		for _, child := range dir.children {
+			if !child.mode.IsDir() {
+				copy(out[int(child.location)*isoSectorSize:], child.data)
+			}
+		}
+	}
+
+	return out
+}
+
+func (img *isoImage) directories() []*isoNode {
+	root := &isoNode{fileNode: img.root}
+	root.parent = root
+
+	// breadth first order as required by the path tables
+	dirs := []*isoNode{root}
+	for i := 0; i < len(dirs); i++ {
+		dir := dirs[i]
+		dir.number = i + 1
+
+		for _, c := range dir.sortedChildren() {
+			child := &isoNode{fileNode: c, parent: dir}
+			dir.children = append(dir.children, child)
+			if child.mode.IsDir() {
+				dirs = append(dirs, child)
+			}
+		}
+	}
+
+	return dirs
+}
+
+func (img *isoImage) volumeDescriptor(joliet bool, total uint32, pathTableSize int, lPath uint32, mPath uint32, root *isoNode) []byte {
+	b := make([]byte, isoSectorSize)
+
+	b[0] = 1
+	if joliet {
+		b[0] = 2
+	}
+	copy(b[1:6], "CD001")
+	b[6] = 1
+
+	if joliet {
+		copy(b[8:40], jolietPadded("", 32))
+		copy(b[40:72], jolietPadded(img.volumeID, 32))
+		copy(b[88:91], "%/E")
+	} else {
+		copy(b[8:40], padded("", 32))
+		copy(b[40:72], padded(img.volumeID, 32))
+	}
+
+	putBothUint32(b[80:88], total)
+	putBothUint16(b[120:124], 1)
+	putBothUint16(b[124:128], 1)
+	putBothUint16(b[128:132], isoSectorSize)
+	putBothUint32(b[132:140], uint32(pathTableSize))
+	binary.LittleEndian.PutUint32(b[140:144], lPath)
+	binary.BigEndian.PutUint32(b[148:152], mPath)
+
+	copy(b[156:190], isoRecord(root, []byte{0}, joliet, nil))
+
+	for _, field := range [][2]int{{190, 128}, {318, 128}, {446, 128}, {574, 128}, {702, 37}, {739, 37}, {776, 37}} {
+		if joliet {
+			copy(b[field[0]:field[0]+field[1]], jolietPadded("", field[1]))
+		} else {
+			copy(b[field[0]:field[0]+field[1]], padded("", field[1]))
+		}
+	}
+
+	for _, offset := range []int{813, 830, 847, 864} {
+		copy(b[offset:offset+16], "0000000000000000")
+	}
+	b[881] = 1
+
+	return b
+}
+
+func isoTerminator() []byte {
+	b := make([]byte, isoSectorSize)
+	b[0] = 255
+	copy(b[1:6], "CD001")
+	b[6] = 1
+
+	return b
+}
+
+func isoPathTable(dirs []*isoNode, joliet bool, order binary.ByteOrder) []byte {
+	buf := new(bytes.Buffer)
+	for _, dir := range dirs {
+		id := []byte{0}
+		location := dir.location
+		if dir.parent != dir {
+			id = dirIdentifier(dir, joliet)
+		}
+		if joliet {
+			location = dir.jLocation
+		}
+
+		entry := make([]byte, 8)
+		entry[0] = byte(len(id))
+		order.PutUint32(entry[2:6], location)
+		order.PutUint16(entry[6:8], uint16(dir.parent.number))
+		buf.Write(entry)
+		buf.Write(id)
+		if len(id)%2 == 1 {
+			buf.WriteByte(0)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func isoDirectorySize(dir *isoNode, joliet bool) int {
+	size := 0
+	for _, rec := range isoDirectoryRecords(dir, joliet) {
+		if size%isoSectorSize+len(rec) > isoSectorSize {
+			size += isoSectorSize - size%isoSectorSize
+		}
+		size += len(rec)
+	}
+
+	return int(sectorsOf(size)) * isoSectorSize
+}
+
+func isoDirectory(dir *isoNode, joliet bool) []byte {
+	out := make([]byte, 0, isoSectorSize)
+	for _, rec := range isoDirectoryRecords(dir, joliet) {
+		if len(out)%isoSectorSize+len(rec) > isoSectorSize {
+			out = append(out, make([]byte, isoSectorSize-len(out)%isoSectorSize)...)
+		}
+		out = append(out, rec...)
+	}
+
+	return out
+}
+
+func isoDirectoryRecords(dir *isoNode, joliet bool) [][]byte {
+	records := [][]byte{
+		isoRecord(dir, []byte{0}, joliet, rockRidgeEntries(dir, false, dir.parent == dir)),
+		isoRecord(dir.parent, []byte{1}, joliet, rockRidgeEntries(dir.parent, false, false)),
+	}
+
+	for _, child := range dir.children {
+		var id []byte
+		if child.mode.IsDir() {
+			id = dirIdentifier(child, joliet)
+		} else if joliet {
+			id = ucs2(child.jolietName + ";1")
+		} else {
+			id = []byte(child.isoName + ";1")
+		}
+
+		records = append(records, isoRecord(child, id, joliet, rockRidgeEntries(child, true, false)))
+	}
+
+	return records
+}
+
+func isoRecord(node *isoNode, id []byte, joliet bool, rr []byte) []byte {
+	location, size := node.location, node.size
+	if joliet {
+		location, size = node.jLocation, node.jSize
+	}
+
+	rec := make([]byte, 33, 255)
+	putBothUint32(rec[2:10], location)
+	putBothUint32(rec[10:18], size)
+	// recording date: 1970-01-01 00:00:00 UTC
+	rec[18] = 70
+	rec[19] = 1
+	rec[20] = 1
+	if node.mode.IsDir() {
+		rec[25] = 2
+	}
+	putBothUint16(rec[28:32], 1)
+	rec[32] = byte(len(id))
+	rec = append(rec, id...)
+	if len(rec)%2 == 1 {
+		rec = append(rec, 0)
+	}
+
+	if !joliet {
+		rec = append(rec, rr...)
+	}
+
+	rec[0] = byte(len(rec))
+
+	return rec
+}
+
+func rockRidgeEntries(node *isoNode, named bool, root bool) []byte {
+	buf := new(bytes.Buffer)
+
+	if root {
+		buf.Write([]byte{'S', 'P', 7, 1, 0xbe, 0xef, 0})
+	}
+
+	mode := uint32(node.mode.Perm())
+	links := uint32(1)
+	if node.mode.IsDir() {
+		mode |= 0o040000
+		links = 2
+	} else {
+		mode |= 0o100000
+	}
+
+	px := make([]byte, 36)
+	copy(px, "PX")
+	px[2] = 36
+	px[3] = 1
+	putBothUint32(px[4:12], mode)
+	putBothUint32(px[12:20], links)
+	buf.Write(px)
+
+	if named {
+		nm := []byte{'N', 'M', byte(5 + len(node.name)), 1, 0}
+		buf.Write(nm)
+		buf.WriteString(node.name)
+	}
+
+	if root {
+		er := []byte{'E', 'R', byte(8 + len(rripIdentifier) + len(rripDescriptor)), 1, byte(len(rripIdentifier)), byte(len(rripDescriptor)), 0, 1}
+		buf.Write(er)
+		buf.WriteString(rripIdentifier)
+		buf.WriteString(rripDescriptor)
+	}
+
+	if buf.Len()%2 == 1 {
+		buf.WriteByte(0)
+	}
+
+	return buf.Bytes()
+}
+
+func dirIdentifier(dir *isoNode, joliet bool) []byte {
+	if joliet {
+		return ucs2(dir.jolietName)
+	}
+
+	return []byte(dir.isoName)
+}
+
+func assignISONames(dir *isoNode) {
+	used := make(map[string]bool)
+	for _, child := range dir.children {
+		child.jolietName = child.name
+
+		base, ext := child.name, ""
+		if !child.mode.IsDir() {
+			if i := strings.LastIndex(child.name, "."); i > 0 {
+				base, ext = child.name[:i], child.name[i+1:]
+			}
+		}
+
+		base, ext = dChars(base), dChars(ext)
+		if len(ext) > 3 {
+			ext = ext[:3]
+		}
+
+		name := isoJoin(truncate(base, 8), ext)
+		for n := 1; used[name]; n++ {
+			suffix := fmt.Sprintf("~%d", n)
+			name = isoJoin(truncate(base, 8-len(suffix))+suffix, ext)
+		}
+
+		used[name] = true
+		child.isoName = name
+	}
+}
+
+func isoJoin(base string, ext string) string {
+	if ext == "" {
+		return base
+	}
+
+	return base + "." + ext
+}
+
+func dChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+
+	return s
+}
+
+func sectorsOf(n int) uint32 {
+	return uint32((n + isoSectorSize - 1) / isoSectorSize)
+}
+
+func putBothUint16(b []byte, v uint16) {
+	binary.LittleEndian.PutUint16(b[0:2], v)
+	binary.BigEndian.PutUint16(b[2:4], v)
+}
+
+func putBothUint32(b []byte, v uint32) {
+	binary.LittleEndian.PutUint32(b[0:4], v)
+	binary.BigEndian.PutUint32(b[4:8], v)
+}
+
+func padded(s string, n int) []byte {
+	b := bytes.Repeat([]byte{' '}, n)
+	copy(b, s)
+
+	return b
+}
+
+func jolietPadded(s string, n int) []byte {
+	b := bytes.Repeat([]byte{0, ' '}, n/2)
+	copy(b, ucs2(s))
+
+	return b
+}
+
+func ucs2(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.BigEndian.PutUint16(b[2*i:], u)
+	}
+
+	return b
+}
+
+func isNotPrintableASCII(r rune) bool {
+	return r < 0x20 || r > 0x7e
+}
@@ -0,0 +1,394 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type isoContent struct {
+	volumeID string
+	files    map[string][]byte
+}
+
+func readISO9660(b []byte, joliet bool) isoContent {
+	typ := byte(1)
+	if joliet {
+		typ = 2
+	}
+
+	var vd []byte
+	for sector := isoSystemArea; ; sector++ {
+		d := b[sector*isoSectorSize : (sector+1)*isoSectorSize]
+		if d[0] == 255 {
+			return isoContent{}
+		}
+		if d[0] == typ {
+			vd = d
+			break
+		}
+	}
+
+	content := isoContent{files: make(map[string][]byte)}
+	if joliet {
+		content.volumeID = strings.TrimRight(decodeUCS2(vd[40:72]), " ")
+	} else {
+		content.volumeID = strings.TrimRight(string(vd[40:72]), " ")
+	}
+
+	var walk func(rec []byte, prefix string)
+	walk = func(rec []byte, prefix string) {
+		location := binary.LittleEndian.Uint32(rec[2:6])
+		size := binary.LittleEndian.Uint32(rec[10:14])
+		extent := b[int(location)*isoSectorSize : int(location)*isoSectorSize+int(size)]
+
+		for offset := 0; offset < len(extent); {
+			length := int(extent[offset])
+			if length == 0 {
+				offset += isoSectorSize - offset%isoSectorSize
+				continue
+			}
+
+			r := extent[offset : offset+length]
+			offset += length
+
+			idLen := int(r[32])
+			id := r[33 : 33+idLen]
+			if idLen == 1 && (id[0] == 0 || id[0] == 1) {
+				continue
+			}
+
+			var name string
+			if joliet {
+				name = strings.TrimSuffix(decodeUCS2(id), ";1")
+			} else {
+				su := r[33+idLen+(idLen+1)%2:]
+				for len(su) >= 4 {
+					if string(su[0:2]) == "NM" {
+						name = string(su[5:su[2]])
+					}
+					su = su[su[2]:]
+				}
+			}
+
+			if r[25]&2 != 0 {
+				walk(r, prefix+name+"/")
+				continue
+			}
+
+			dataLocation := int(binary.LittleEndian.Uint32(r[2:6]))
+			dataSize := int(binary.LittleEndian.Uint32(r[10:14]))
+			content.files[prefix+name] = b[dataLocation*isoSectorSize : dataLocation*isoSectorSize+dataSize]
+		}
+	}
+	walk(vd[156:190], "")
+
+	return content
+}
+
+func decodeUCS2(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(b[2*i:])
+	}
+
+	return string(utf16.Decode(units))
+}
+
+func TestNewISO9660Image(t *testing.T) {
+	type args struct {
+		volumeID string
+	}
+
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case",
+			args: args{
+				volumeID: "cidata",
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: empty",
+			args: args{
+				volumeID: "",
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrInvalidVolumeID},
+			},
+		},
+		{
+			name: "negative case: too long",
+			args: args{
+				volumeID: "cidata-cidata-cidata-cidata-cidata",
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrInvalidVolumeID},
+			},
+		},
+		{
+			name: "negative case: non ascii",
+			args: args{
+				volumeID: "シードデータ",
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrInvalidVolumeID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewISO9660Image(tt.args.volumeID)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestISO9660Image_WriteFile(t *testing.T) {
+	type args struct {
+		name string
+	}
+
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     []args
+		expected expected
+	}{
+		{
+			name: "positive case: nested",
+			args: []args{
+				{name: "openstack/latest/user_data"},
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: invalid path",
+			args: []args{
+				{name: "/user-data"},
+			},
+			expected: expected{
+				err: &Error{Op: "write", Err: ErrInvalidPath},
+			},
+		},
+		{
+			name: "negative case: rock ridge name too long",
+			args: []args{
+				{name: strings.Repeat("名", 64)},
+			},
+			expected: expected{
+				err: &Error{Op: "write", Err: ErrInvalidPath},
+			},
+		},
+		{
+			name: "negative case: directory name too long",
+			args: []args{
+				{name: strings.Repeat("名", 55) + "a/user-data"},
+			},
+			expected: expected{
+				err: &Error{Op: "write", Err: ErrInvalidPath},
+			},
+		},
+		{
+			name: "negative case: file as directory",
+			args: []args{
+				{name: "openstack"},
+				{name: "openstack/latest/user_data"},
+			},
+			expected: expected{
+				err: &Error{Op: "write", Err: ErrInvalidPath},
+			},
+		},
+		{
+			name: "negative case: directory as file",
+			args: []args{
+				{name: "openstack/latest/user_data"},
+				{name: "openstack"},
+			},
+			expected: expected{
+				err: &Error{Op: "write", Err: ErrInvalidPath},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, _ := NewISO9660Image("cidata")
+
+			var err error
+			for _, args := range tt.args {
+				err = img.WriteFile(args.name, []byte{}, 0o644)
+			}
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestISO9660Image_Render(t *testing.T) {
+	type expected struct {
+		res isoContent
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		image    ISO9660Image
+		expected expected
+	}{
+		{
+			name: "positive case: nocloud seed",
+			image: func() ISO9660Image {
+				img, _ := NewISO9660Image("cidata")
+
+				seed := Seed{
+					UserData: func() Multipart {
+						m, _ := NewMultipart()
+						m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London")))
+						return m
+					}(),
+					MetaData: MetaData{InstanceID: "iid-local01"},
+				}
+				_ = seed.Write(img)
+
+				return img
+			}(),
+			expected: expected{
+				res: isoContent{
+					volumeID: "cidata",
+					files: map[string][]byte{
+						"user-data": []byte("Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+							"Mime-Version: 1.0\r\n" +
+							"\r\n" +
+							"--+Go+User+Data+Boundary==\r\n" +
+							"Content-Transfer-Encoding: 7bit\r\n" +
+							"Content-Type: text/cloud-config; charset=us-ascii\r\n" +
+							"\r\n" +
+							"#cloud-config\n" +
+							"timezone: Europe/London\r\n" +
+							"\r\n" +
+							"--+Go+User+Data+Boundary==--\r\n"),
+						"meta-data": []byte("instance-id: iid-local01\n"),
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: nested and large",
+			image: func() ISO9660Image {
+				img, _ := NewISO9660Image("config-2")
+
+				_ = img.WriteFile("openstack/latest/user_data", bytes.Repeat([]byte("a"), 5000), 0o644)
+				_ = img.WriteFile("openstack/latest/meta_data.json", []byte("{}"), 0o644)
+				_ = img.WriteFile("openstack/latest/network_data.json", []byte{}, 0o644)
+
+				return img
+			}(),
+			expected: expected{
+				res: isoContent{
+					volumeID: "config-2",
+					files: map[string][]byte{
+						"openstack/latest/user_data":         bytes.Repeat([]byte("a"), 5000),
+						"openstack/latest/meta_data.json":    []byte("{}"),
+						"openstack/latest/network_data.json": {},
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: longest names",
+			image: func() ISO9660Image {
+				img, _ := NewISO9660Image("cidata")
+
+				_ = img.WriteFile(strings.Repeat("名", 55)+"/"+strings.Repeat("a", 64), []byte("ascii"), 0o644)
+				_ = img.WriteFile(strings.Repeat("名", 53)+".txt", []byte("cjk"), 0o644)
+
+				return img
+			}(),
+			expected: expected{
+				res: isoContent{
+					volumeID: "cidata",
+					files: map[string][]byte{
+						strings.Repeat("名", 55) + "/" + strings.Repeat("a", 64): []byte("ascii"),
+						strings.Repeat("名", 53) + ".txt":                        []byte("cjk"),
+					},
+				},
+				err: nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.image.Render(buf)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, 0, buf.Len()%isoSectorSize)
+				assert.Equal(t, tt.expected.res, readISO9660(buf.Bytes(), false))
+				assert.Equal(t, tt.expected.res, readISO9660(buf.Bytes(), true))
+
+				again := new(bytes.Buffer)
+				assert.NoError(t, tt.image.Render(again))
+				assert.Equal(t, buf.Bytes(), again.Bytes())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
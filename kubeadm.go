@@ -0,0 +1,246 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	kubeadmInitConfigPath = "/run/kubeadm/kubeadm.yaml"
+	kubeadmJoinConfigPath = "/run/kubeadm/kubeadm-join-config.yaml"
+	kubeadmSuccessPath    = "/run/cluster-api/bootstrap-success.complete"
+	kubeadmSecretType     = "cluster.x-k8s.io/secret"
+	kubeadmClusterLabel   = "cluster.x-k8s.io/cluster-name"
+	kubeadmFormat         = "cloud-config"
+)
+
+var (
+	kubeadmFileEncodings = []string{"", "base64", "gzip", "gzip+base64"}
+)
+
+type KubeadmConfig struct {
+	Name                 string
+	Namespace            string
+	ClusterName          string
+	Files                []KubeadmFile
+	PreKubeadmCommands   []string
+	PostKubeadmCommands  []string
+	Users                []KubeadmUser
+	NTP                  *KubeadmNTP
+	ClusterConfiguration string
+	InitConfiguration    string
+	JoinConfiguration    string
+}
+
+type KubeadmFile struct {
+	Path        string `yaml:"path"`
+	Owner       string `yaml:"owner,omitempty"`
+	Permissions string `yaml:"permissions,omitempty"`
+	Encoding    string `yaml:"encoding,omitempty"`
+	Append      bool   `yaml:"append,omitempty"`
+	Content     string `yaml:"content"`
+}
+
+type KubeadmUser struct {
+	Name              string   `yaml:"name"`
+	Gecos             string   `yaml:"gecos,omitempty"`
+	Groups            string   `yaml:"groups,omitempty"`
+	HomeDir           string   `yaml:"homedir,omitempty"`
+	Inactive          *bool    `yaml:"inactive,omitempty"`
+	Shell             string   `yaml:"shell,omitempty"`
+	Passwd            string   `yaml:"passwd,omitempty"`
+	PrimaryGroup      string   `yaml:"primary_group,omitempty"`
+	LockPassword      *bool    `yaml:"lock_passwd,omitempty"`
+	Sudo              string   `yaml:"sudo,omitempty"`
+	SSHAuthorizedKeys []string `yaml:"ssh_authorized_keys,omitempty"`
+}
+
+type KubeadmNTP struct {
+	Servers []string `yaml:"servers,omitempty"`
+	Enabled *bool    `yaml:"enabled,omitempty"`
+}
+
+func (c *KubeadmConfig) BootstrapData() (Multipart, error) {
+	if err := c.validate(); err != nil {
+		logger.Println("failed to build bootstrap data", "func", getFuncName(), "name", c.Name, "error", err)
+		return nil, err
+	}
+
+	configPath := kubeadmInitConfigPath
+	configContent := c.InitConfiguration
+	command := "kubeadm init --config " + kubeadmInitConfigPath
+	if c.JoinConfiguration != "" {
+		configPath = kubeadmJoinConfigPath
+		configContent = c.JoinConfiguration
+		command = "kubeadm join --config " + kubeadmJoinConfigPath
+	} else if c.ClusterConfiguration != "" {
+		configContent = strings.TrimSuffix(c.ClusterConfiguration, "\n") + "\n---\n" + configContent
+	}
+
+	files := append(append([]KubeadmFile{}, c.Files...), KubeadmFile{
+		Path:        configPath,
+		Owner:       "root:root",
+		Permissions: "0640",
+		Content:     configContent,
+	})
+
+	doc := struct {
+		WriteFiles []KubeadmFile `yaml:"write_files"`
+		Users      []KubeadmUser `yaml:"users,omitempty"`
+		NTP        *KubeadmNTP   `yaml:"ntp,omitempty"`
+	}{
+		WriteFiles: files,
+		Users:      c.Users,
+		NTP:        c.NTP,
+	}
+
+	cfg := bytes.NewBufferString("#cloud-config\n")
+	if err := renderYAML(cfg, &doc); err != nil {
+		logger.Println("failed to build bootstrap data", "func", getFuncName(), "name", c.Name, "error", err)
+		return nil, err
+	}
+
+	script := new(bytes.Buffer)
+	fmt.Fprint(script, "#!/bin/bash\n")
+	fmt.Fprint(script, "set -euo pipefail\n")
+	for _, cmd := range c.PreKubeadmCommands {
+		fmt.Fprintf(script, "%s\n", cmd)
+	}
+	fmt.Fprintf(script, "%s\n", command)
+	fmt.Fprintf(script, "mkdir -p %s && echo success > %s\n", path.Dir(kubeadmSuccessPath), kubeadmSuccessPath)
+	for _, cmd := range c.PostKubeadmCommands {
+		fmt.Fprintf(script, "%s\n", cmd)
+	}
+
+	m, err := NewMultipart()
+	if err != nil {
+		logger.Println("failed to build bootstrap data", "func", getFuncName(), "name", c.Name, "error", err)
+		return nil, err
+	}
+
+	for _, part := range []struct {
+		mediaType MediaType
+		body      []byte
+	}{
+		{mediaType: MediaTypeCloudConfig, body: cfg.Bytes()},
+		{mediaType: MediaTypeXShellscript, body: script.Bytes()},
+	} {
+		p, err := NewPart(part.mediaType, part.body)
+		if err != nil {
+			logger.Println("failed to build bootstrap data", "func", getFuncName(), "name", c.Name, "error", err)
+			return nil, err
+		}
+		m.Append(p)
+	}
+
+	return m, nil
+}
+
+func (c *KubeadmConfig) RenderSecret(w io.Writer) error {
+	if c.Name == "" {
+		err := &Error{Op: "render", Err: ErrInvalidName}
+		logger.Println("failed to render bootstrap secret", "func", getFuncName(), "name", c.Name, "error", err)
+		return err
+	}
+
+	m, err := c.BootstrapData()
+	if err != nil {
+		logger.Println("failed to render bootstrap secret", "func", getFuncName(), "name", c.Name, "error", err)
+		return err
+	}
+
+	value, err := renderBytes(m)
+	if err != nil {
+		logger.Println("failed to render bootstrap secret", "func", getFuncName(), "name", c.Name, "error", err)
+		return err
+	}
+
+	var labels map[string]string
+	if c.ClusterName != "" {
+		labels = map[string]string{kubeadmClusterLabel: c.ClusterName}
+	}
+
+	doc := struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name      string            `yaml:"name"`
+			Namespace string            `yaml:"namespace,omitempty"`
+			Labels    map[string]string `yaml:"labels,omitempty"`
+		} `yaml:"metadata"`
+		Type string            `yaml:"type"`
+		Data map[string]string `yaml:"data"`
+	}{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Type:       kubeadmSecretType,
+		Data: map[string]string{
+			"format": base64.StdEncoding.EncodeToString([]byte(kubeadmFormat)),
+			"value":  base64.StdEncoding.EncodeToString(value),
+		},
+	}
+	doc.Metadata.Name = c.Name
+	doc.Metadata.Namespace = c.Namespace
+	doc.Metadata.Labels = labels
+
+	if err := renderYAML(w, &doc); err != nil {
+		logger.Println("failed to render bootstrap secret", "func", getFuncName(), "name", c.Name, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *KubeadmConfig) validate() error {
+	if (c.InitConfiguration == "") == (c.JoinConfiguration == "") {
+		return &Error{Op: "validate", Err: ErrInvalidConfiguration}
+	}
+
+	if c.JoinConfiguration != "" && c.ClusterConfiguration != "" {
+		return &Error{Op: "validate", Err: ErrInvalidConfiguration}
+	}
+
+	for _, f := range c.Files {
+		if !path.IsAbs(f.Path) {
+			return &Error{Op: "validate", Err: ErrInvalidPath}
+		}
+
+		if !slices.Contains(kubeadmFileEncodings, f.Encoding) {
+			return &Error{Op: "validate", Err: ErrInvalidEncoding}
+		}
+	}
+
+	for _, u := range c.Users {
+		if u.Name == "" {
+			return &Error{Op: "validate", Err: ErrInvalidName}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestKubeadmConfig_BootstrapData(t *testing.T) {
+	enabled := true
+
+	type expected struct {
+		mediaTypes []MediaType
+		bodies     []string
+		err        error
+	}
+
+	tests := []struct {
+		name     string
+		config   KubeadmConfig
+		expected expected
+	}{
+		{
+			name: "positive case: init",
+			config: KubeadmConfig{
+				Files: []KubeadmFile{
+					{Path: "/etc/sysctl.d/k8s.conf", Permissions: "0644", Content: "net.ipv4.ip_forward = 1\n"},
+				},
+				PreKubeadmCommands:   []string{"swapoff -a"},
+				PostKubeadmCommands:  []string{"echo done"},
+				Users:                []KubeadmUser{{Name: "capi", Sudo: "ALL=(ALL) NOPASSWD:ALL", SSHAuthorizedKeys: []string{"ssh-ed25519 AAAA"}}},
+				NTP:                  &KubeadmNTP{Servers: []string{"time.example.com"}, Enabled: &enabled},
+				ClusterConfiguration: "apiVersion: kubeadm.k8s.io/v1beta3\nkind: ClusterConfiguration\n",
+				InitConfiguration:    "apiVersion: kubeadm.k8s.io/v1beta3\nkind: InitConfiguration\n",
+			},
+			expected: expected{
+				mediaTypes: []MediaType{MediaTypeCloudConfig, MediaTypeXShellscript},
+				bodies: []string{
+					"#cloud-config\n" +
+						"write_files:\n" +
+						"  - path: /etc/sysctl.d/k8s.conf\n" +
+						"    permissions: \"0644\"\n" +
+						"    content: |\n" +
+						"      net.ipv4.ip_forward = 1\n" +
+						"  - path: /run/kubeadm/kubeadm.yaml\n" +
+						"    owner: root:root\n" +
+						"    permissions: \"0640\"\n" +
+						"    content: |\n" +
+						"      apiVersion: kubeadm.k8s.io/v1beta3\n" +
+						"      kind: ClusterConfiguration\n" +
+						"      ---\n" +
+						"      apiVersion: kubeadm.k8s.io/v1beta3\n" +
+						"      kind: InitConfiguration\n" +
+						"users:\n" +
+						"  - name: capi\n" +
+						"    sudo: ALL=(ALL) NOPASSWD:ALL\n" +
+						"    ssh_authorized_keys:\n" +
+						"      - ssh-ed25519 AAAA\n" +
+						"ntp:\n" +
+						"  servers:\n" +
+						"    - time.example.com\n" +
+						"  enabled: true\n",
+					"#!/bin/bash\n" +
+						"set -euo pipefail\n" +
+						"swapoff -a\n" +
+						"kubeadm init --config /run/kubeadm/kubeadm.yaml\n" +
+						"mkdir -p /run/cluster-api && echo success > /run/cluster-api/bootstrap-success.complete\n" +
+						"echo done\n",
+				},
+			},
+		},
+		{
+			name: "positive case: join",
+			config: KubeadmConfig{
+				JoinConfiguration: "apiVersion: kubeadm.k8s.io/v1beta3\nkind: JoinConfiguration\n",
+			},
+			expected: expected{
+				mediaTypes: []MediaType{MediaTypeCloudConfig, MediaTypeXShellscript},
+				bodies: []string{
+					"#cloud-config\n" +
+						"write_files:\n" +
+						"  - path: /run/kubeadm/kubeadm-join-config.yaml\n" +
+						"    owner: root:root\n" +
+						"    permissions: \"0640\"\n" +
+						"    content: |\n" +
+						"      apiVersion: kubeadm.k8s.io/v1beta3\n" +
+						"      kind: JoinConfiguration\n",
+					"#!/bin/bash\n" +
+						"set -euo pipefail\n" +
+						"kubeadm join --config /run/kubeadm/kubeadm-join-config.yaml\n" +
+						"mkdir -p /run/cluster-api && echo success > /run/cluster-api/bootstrap-success.complete\n",
+				},
+			},
+		},
+		{
+			name:   "negative case: neither init nor join",
+			config: KubeadmConfig{},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidConfiguration},
+			},
+		},
+		{
+			name: "negative case: both init and join",
+			config: KubeadmConfig{
+				InitConfiguration: "kind: InitConfiguration\n",
+				JoinConfiguration: "kind: JoinConfiguration\n",
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidConfiguration},
+			},
+		},
+		{
+			name: "negative case: cluster configuration with join",
+			config: KubeadmConfig{
+				ClusterConfiguration: "kind: ClusterConfiguration\n",
+				JoinConfiguration:    "kind: JoinConfiguration\n",
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidConfiguration},
+			},
+		},
+		{
+			name: "negative case: relative file path",
+			config: KubeadmConfig{
+				Files:             []KubeadmFile{{Path: "etc/foo", Content: "foo"}},
+				JoinConfiguration: "kind: JoinConfiguration\n",
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidPath},
+			},
+		},
+		{
+			name: "negative case: invalid file encoding",
+			config: KubeadmConfig{
+				Files:             []KubeadmFile{{Path: "/etc/foo", Encoding: "zstd", Content: "foo"}},
+				JoinConfiguration: "kind: JoinConfiguration\n",
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidEncoding},
+			},
+		},
+		{
+			name: "negative case: unnamed user",
+			config: KubeadmConfig{
+				Users:             []KubeadmUser{{Shell: "/bin/bash"}},
+				JoinConfiguration: "kind: JoinConfiguration\n",
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidName},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.config.BootstrapData()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+
+				parts := actual.Parts()
+				mediaTypes := make([]MediaType, 0, len(parts))
+				bodies := make([]string, 0, len(parts))
+				for _, p := range parts {
+					mediaTypes = append(mediaTypes, p.MediaType())
+					bodies = append(bodies, string(p.Body()))
+				}
+				assert.Equal(t, tt.expected.mediaTypes, mediaTypes)
+				assert.Equal(t, tt.expected.bodies, bodies)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestKubeadmConfig_RenderSecret(t *testing.T) {
+	type expected struct {
+		metadata map[string]interface{}
+		err      error
+	}
+
+	tests := []struct {
+		name     string
+		config   KubeadmConfig
+		expected expected
+	}{
+		{
+			name: "positive case: with cluster name",
+			config: KubeadmConfig{
+				Name:              "worker-0",
+				Namespace:         "default",
+				ClusterName:       "cluster-0",
+				JoinConfiguration: "kind: JoinConfiguration\n",
+			},
+			expected: expected{
+				metadata: map[string]interface{}{
+					"name":      "worker-0",
+					"namespace": "default",
+					"labels": map[string]interface{}{
+						"cluster.x-k8s.io/cluster-name": "cluster-0",
+					},
+				},
+			},
+		},
+		{
+			name: "positive case: without cluster name",
+			config: KubeadmConfig{
+				Name:              "worker-0",
+				JoinConfiguration: "kind: JoinConfiguration\n",
+			},
+			expected: expected{
+				metadata: map[string]interface{}{
+					"name": "worker-0",
+				},
+			},
+		},
+		{
+			name: "negative case: missing name",
+			config: KubeadmConfig{
+				JoinConfiguration: "kind: JoinConfiguration\n",
+			},
+			expected: expected{
+				err: &Error{Op: "render", Err: ErrInvalidName},
+			},
+		},
+		{
+			name: "negative case: invalid config",
+			config: KubeadmConfig{
+				Name: "worker-0",
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidConfiguration},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			err := tt.config.RenderSecret(w)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+
+				var doc struct {
+					APIVersion string                 `yaml:"apiVersion"`
+					Kind       string                 `yaml:"kind"`
+					Metadata   map[string]interface{} `yaml:"metadata"`
+					Type       string                 `yaml:"type"`
+					Data       map[string]string      `yaml:"data"`
+				}
+				assert.NoError(t, yaml.Unmarshal(w.Bytes(), &doc))
+				assert.Equal(t, "v1", doc.APIVersion)
+				assert.Equal(t, "Secret", doc.Kind)
+				assert.Equal(t, tt.expected.metadata, doc.Metadata)
+				assert.Equal(t, "cluster.x-k8s.io/secret", doc.Type)
+
+				format, err := base64.StdEncoding.DecodeString(doc.Data["format"])
+				assert.NoError(t, err)
+				assert.Equal(t, "cloud-config", string(format))
+
+				value, err := base64.StdEncoding.DecodeString(doc.Data["value"])
+				assert.NoError(t, err)
+				m, err := Parse(bytes.NewReader(value))
+				assert.NoError(t, err)
+				assert.Len(t, m.Parts(), 2)
+				assert.True(t, strings.HasPrefix(string(m.Parts()[1].Body()), "#!/bin/bash\n"))
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+const (
+	kubeVirtVolumeName    = "cloudinitdisk"
+	kubeVirtSecretUser    = "userdata"
+	kubeVirtSecretNetwork = "networkdata"
+
+	// limit enforced by the KubeVirt admission webhook on inline data
+	kubeVirtInlineLimit = 2048
+)
+
+type KubeVirtSource string
+
+const (
+	KubeVirtSourceNoCloud     KubeVirtSource = "cloudInitNoCloud"
+	KubeVirtSourceConfigDrive KubeVirtSource = "cloudInitConfigDrive"
+)
+
+type KubeVirtCloudInit struct {
+	VolumeName      string
+	Source          KubeVirtSource
+	UserData        Multipart
+	NetworkConfig   Renderer
+	SecretName      string
+	SecretNamespace string
+	InlineLimit     int
+	Encoding        Encoding
+}
+
+type kubeVirtSecretRef struct {
+	Name string `yaml:"name"`
+}
+
+type kubeVirtCloudInitSource struct {
+	UserData             string             `yaml:"userData,omitempty"`
+	UserDataBase64       string             `yaml:"userDataBase64,omitempty"`
+	SecretRef            *kubeVirtSecretRef `yaml:"secretRef,omitempty"`
+	NetworkData          string             `yaml:"networkData,omitempty"`
+	NetworkDataBase64    string             `yaml:"networkDataBase64,omitempty"`
+	NetworkDataSecretRef *kubeVirtSecretRef `yaml:"networkDataSecretRef,omitempty"`
+}
+
+type kubeVirtVolume struct {
+	Name                 string                   `yaml:"name"`
+	CloudInitNoCloud     *kubeVirtCloudInitSource `yaml:"cloudInitNoCloud,omitempty"`
+	CloudInitConfigDrive *kubeVirtCloudInitSource `yaml:"cloudInitConfigDrive,omitempty"`
+}
+
+type kubeVirtSecretMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type kubeVirtSecret struct {
+	APIVersion string                 `yaml:"apiVersion"`
+	Kind       string                 `yaml:"kind"`
+	Metadata   kubeVirtSecretMetadata `yaml:"metadata"`
+	Type       string                 `yaml:"type"`
+	Data       map[string]string      `yaml:"data"`
+}
+
+func (k *KubeVirtCloudInit) Manifests() ([]byte, []byte, error) {
+	source := k.Source
+	if source == "" {
+		source = KubeVirtSourceNoCloud
+	}
+
+	if source != KubeVirtSourceNoCloud && source != KubeVirtSourceConfigDrive {
+		err := &Error{Op: "encode", Err: ErrInvalidType}
+		logger.Println("failed to build kubevirt manifests", "func", getFuncName(), "source", k.Source, "error", err)
+		return nil, nil, err
+	}
+
+	if k.Encoding != "" && k.Encoding != Encoding7bit && k.Encoding != EncodingBase64 {
+		err := &Error{Op: "encode", Err: ErrInvalidEncoding}
+		logger.Println("failed to build kubevirt manifests", "func", getFuncName(), "encoding", k.Encoding, "error", err)
+		return nil, nil, err
+	}
+
+	var userData, networkData []byte
+	if k.UserData != nil {
+		b, err := renderBytes(k.UserData)
+		if err != nil {
+			logger.Println("failed to build kubevirt manifests", "func", getFuncName(), "error", err)
+			return nil, nil, err
+		}
+
+		userData = b
+	}
+
+	if k.NetworkConfig != nil {
+		b, err := renderBytes(k.NetworkConfig)
+		if err != nil {
+			logger.Println("failed to build kubevirt manifests", "func", getFuncName(), "error", err)
+			return nil, nil, err
+		}
+
+		networkData = b
+	}
+
+	limit := k.InlineLimit
+	if limit <= 0 {
+		limit = kubeVirtInlineLimit
+	}
+
+	volumeName := k.VolumeName
+	if volumeName == "" {
+		volumeName = kubeVirtVolumeName
+	}
+
+	var cloudInit kubeVirtCloudInitSource
+	var secret []byte
+
+	// binary, gzipped or CRLF payloads only survive inlining as base64, which then counts against the limit
+	inlineUserData, inlineNetworkData := string(userData), string(networkData)
+	if k.Encoding == EncodingBase64 {
+		inlineUserData, inlineNetworkData = "", ""
+		if userData != nil {
+			inlineUserData = base64.StdEncoding.EncodeToString(userData)
+		}
+		if networkData != nil {
+			inlineNetworkData = base64.StdEncoding.EncodeToString(networkData)
+		}
+	}
+
+	if len(inlineUserData) <= limit && len(inlineNetworkData) <= limit {
+		if k.Encoding == EncodingBase64 {
+			cloudInit.UserDataBase64 = inlineUserData
+			cloudInit.NetworkDataBase64 = inlineNetworkData
+		} else {
+			cloudInit.UserData = inlineUserData
+			cloudInit.NetworkData = inlineNetworkData
+		}
+	} else {
+		if k.SecretName == "" {
+			err := &Error{Op: "encode", Err: ErrInvalidName}
+			logger.Println("failed to build kubevirt manifests", "func", getFuncName(), "error", err)
+			return nil, nil, err
+		}
+
+		data := make(map[string]string, 2)
+		if userData != nil {
+			data[kubeVirtSecretUser] = base64.StdEncoding.EncodeToString(userData)
+			cloudInit.SecretRef = &kubeVirtSecretRef{Name: k.SecretName}
+		}
+		if networkData != nil {
+			data[kubeVirtSecretNetwork] = base64.StdEncoding.EncodeToString(networkData)
+			cloudInit.NetworkDataSecretRef = &kubeVirtSecretRef{Name: k.SecretName}
+		}
+
+		b, err := marshalYAML(&kubeVirtSecret{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Metadata:   kubeVirtSecretMetadata{Name: k.SecretName, Namespace: k.SecretNamespace},
+			Type:       "Opaque",
+			Data:       data,
+		})
+		if err != nil {
+			logger.Println("failed to build kubevirt manifests", "func", getFuncName(), "error", err)
+			return nil, nil, err
+		}
+
+		secret = b
+	}
+
+	vol := kubeVirtVolume{Name: volumeName}
+	if source == KubeVirtSourceNoCloud {
+		vol.CloudInitNoCloud = &cloudInit
+	} else {
+		vol.CloudInitConfigDrive = &cloudInit
+	}
+
+	volume, err := marshalYAML([]kubeVirtVolume{vol})
+	if err != nil {
+		logger.Println("failed to build kubevirt manifests", "func", getFuncName(), "error", err)
+		return nil, nil, err
+	}
+
+	return volume, secret, nil
+}
+
+func (k *KubeVirtCloudInit) Render(w io.Writer) error {
+	volume, secret, err := k.Manifests()
+	if err != nil {
+		logger.Println("failed to render kubevirt manifests", "func", getFuncName(), "error", err)
+		return err
+	}
+
+	if _, err := w.Write(volume); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render kubevirt manifests", "func", getFuncName(), "error", err)
+		return err
+	}
+
+	if secret == nil {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "---\n%s", secret); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render kubevirt manifests", "func", getFuncName(), "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubeVirtCloudInit_Render(t *testing.T) {
+	empty := func() Multipart {
+		m, _ := NewMultipart()
+		return m
+	}
+
+	network := &NetworkConfigV2{
+		Ethernets: map[string]NetworkV2Ethernet{
+			"eth0": {NetworkV2Device: NetworkV2Device{DHCP4: true}},
+		},
+	}
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name      string
+		cloudInit KubeVirtCloudInit
+		expected  expected
+	}{
+		{
+			name: "positive case: inline",
+			cloudInit: KubeVirtCloudInit{
+				UserData:      empty(),
+				NetworkConfig: network,
+			},
+			expected: expected{
+				res: "- name: cloudinitdisk\n" +
+					"  cloudInitNoCloud:\n" +
+					"    userData: \"Content-Type: multipart/mixed; boundary=\\\"+Go+User+Data+Boundary==\\\"\\r\\nMime-Version: 1.0\\r\\n\\r\\n--+Go+User+Data+Boundary==--\\r\\n\"\n" +
+					"    networkData: |\n" +
+					"      version: 2\n" +
+					"      ethernets:\n" +
+					"        eth0:\n" +
+					"          dhcp4: true\n",
+			},
+		},
+		{
+			name: "positive case: secret",
+			cloudInit: KubeVirtCloudInit{
+				VolumeName:      "seed",
+				Source:          KubeVirtSourceConfigDrive,
+				UserData:        empty(),
+				NetworkConfig:   network,
+				SecretName:      "vm01-cloud-init",
+				SecretNamespace: "vms",
+				InlineLimit:     64,
+			},
+			expected: expected{
+				res: "- name: seed\n" +
+					"  cloudInitConfigDrive:\n" +
+					"    secretRef:\n" +
+					"      name: vm01-cloud-init\n" +
+					"    networkDataSecretRef:\n" +
+					"      name: vm01-cloud-init\n" +
+					"---\n" +
+					"apiVersion: v1\n" +
+					"kind: Secret\n" +
+					"metadata:\n" +
+					"  name: vm01-cloud-init\n" +
+					"  namespace: vms\n" +
+					"type: Opaque\n" +
+					"data:\n" +
+					"  networkdata: dmVyc2lvbjogMgpldGhlcm5ldHM6CiAgZXRoMDoKICAgIGRoY3A0OiB0cnVlCg==\n" +
+					"  userdata: Q29udGVudC1UeXBlOiBtdWx0aXBhcnQvbWl4ZWQ7IGJvdW5kYXJ5PSIrR28rVXNlcitEYXRhK0JvdW5kYXJ5PT0iDQpNaW1lLVZlcnNpb246IDEuMA0KDQotLStHbytVc2VyK0RhdGErQm91bmRhcnk9PS0tDQo=\n",
+			},
+		},
+		{
+			name: "positive case: inline base64",
+			cloudInit: KubeVirtCloudInit{
+				UserData:      empty(),
+				NetworkConfig: network,
+				Encoding:      EncodingBase64,
+			},
+			expected: expected{
+				res: "- name: cloudinitdisk\n" +
+					"  cloudInitNoCloud:\n" +
+					"    userDataBase64: Q29udGVudC1UeXBlOiBtdWx0aXBhcnQvbWl4ZWQ7IGJvdW5kYXJ5PSIrR28rVXNlcitEYXRhK0JvdW5kYXJ5PT0iDQpNaW1lLVZlcnNpb246IDEuMA0KDQotLStHbytVc2VyK0RhdGErQm91bmRhcnk9PS0tDQo=\n" +
+					"    networkDataBase64: dmVyc2lvbjogMgpldGhlcm5ldHM6CiAgZXRoMDoKICAgIGRoY3A0OiB0cnVlCg==\n",
+			},
+		},
+		{
+			name: "negative case: missing secret name",
+			cloudInit: KubeVirtCloudInit{
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+strings.Repeat("#", 2048))))
+					return m
+				}(),
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrInvalidName},
+			},
+		},
+		{
+			name: "negative case: base64 over inline limit",
+			cloudInit: KubeVirtCloudInit{
+				UserData:    empty(),
+				InlineLimit: 120,
+				Encoding:    EncodingBase64,
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrInvalidName},
+			},
+		},
+		{
+			name: "negative case: unknown encoding",
+			cloudInit: KubeVirtCloudInit{
+				UserData: empty(),
+				Encoding: "gzip",
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrInvalidEncoding},
+			},
+		},
+		{
+			name: "negative case: unknown source",
+			cloudInit: KubeVirtCloudInit{
+				Source:   "cloudInitISO",
+				UserData: empty(),
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrInvalidType},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.cloudInit.Render(buf)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, buf.String())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
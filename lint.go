@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+)
+
+func Lint(p Part) []error {
+	warns := make([]error, 0)
+
+	spec, ok := lookupMediaType(p.MediaType())
+	if !ok {
+		warns = append(warns, &Error{Op: "lint", Err: ErrUnknownMediaType})
+		return warns
+	}
+
+	body := p.Body()
+
+	// cloudbase-init scripts carry their own markers and CRLF line endings
+	windows := spec.Script && isWindowsScript(p)
+
+	if spec.Marker != "" && !windows && !bytes.HasPrefix(body, []byte(spec.Marker)) {
+		warns = append(warns, &Error{Op: "lint", Err: ErrMarkerMismatch})
+	}
+
+	if spec.Script && !windows && bytes.Contains(body, []byte("\r\n")) {
+		warns = append(warns, &Error{Op: "lint", Err: ErrCRLFLineEndings})
+	}
+
+	if spec.Validator != nil {
+		if err := spec.Validator(body); err != nil {
+			warns = append(warns, &Error{Op: "lint", Err: err})
+		}
+	}
+
+	return warns
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	errNoBootstrap := errors.New("no bootstrap")
+
+	acme := MediaTypeSpec{
+		MediaType: "text/x-acme-bootstrap",
+		Marker:    "#acme-bootstrap",
+		Validator: func(body []byte) error {
+			if !bytes.Contains(body, []byte("bootstrap")) {
+				return errNoBootstrap
+			}
+
+			return nil
+		},
+	}
+
+	type expected struct {
+		res []error
+	}
+
+	tests := []struct {
+		name     string
+		part     Part
+		expected expected
+	}{
+		{
+			name: "positive case: clean script",
+			part: mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'Hello World'\n")),
+			expected: expected{
+				res: []error{},
+			},
+		},
+		{
+			name: "positive case: windows script",
+			part: mustNewPart(MediaTypeXShellscript, []byte("#ps1_sysnative\r\n"+"Write-Host 'Hello World'\r\n")),
+			expected: expected{
+				res: []error{},
+			},
+		},
+		{
+			name: "positive case: registered type",
+			part: &part{
+				header:    NewHeader(),
+				body:      []byte("#acme-bootstrap\n" + "bootstrap: true\n"),
+				mediaType: acme.MediaType,
+			},
+			expected: expected{
+				res: []error{},
+			},
+		},
+		{
+			name: "negative case: marker mismatch",
+			part: mustNewPart(MediaTypeCloudConfig, []byte("timezone: Europe/London\n")),
+			expected: expected{
+				res: []error{&Error{Op: "lint", Err: ErrMarkerMismatch}},
+			},
+		},
+		{
+			name: "negative case: crlf script",
+			part: mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/bash\r\n"+"echo 'Hello World'\r\n")),
+			expected: expected{
+				res: []error{&Error{Op: "lint", Err: ErrCRLFLineEndings}},
+			},
+		},
+		{
+			name: "negative case: registered type",
+			part: &part{
+				header:    NewHeader(),
+				body:      []byte("hello\n"),
+				mediaType: acme.MediaType,
+			},
+			expected: expected{
+				res: []error{
+					&Error{Op: "lint", Err: ErrMarkerMismatch},
+					&Error{Op: "lint", Err: errNoBootstrap},
+				},
+			},
+		},
+		{
+			name: "negative case: unknown type",
+			part: &part{
+				header:    NewHeader(),
+				body:      []byte("hello\n"),
+				mediaType: "text/x-unknown",
+			},
+			expected: expected{
+				res: []error{&Error{Op: "lint", Err: ErrUnknownMediaType}},
+			},
+		},
+	}
+
+	assert.NoError(t, RegisterMediaType(acme))
+	t.Cleanup(func() { unregisterMediaType(acme.MediaType) })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Lint(tt.part)
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
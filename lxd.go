@@ -0,0 +1,121 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"io"
+)
+
+const (
+	lxdUserData      = "cloud-init.user-data"
+	lxdVendorData    = "cloud-init.vendor-data"
+	lxdNetworkConfig = "cloud-init.network-config"
+)
+
+type LXDConfig struct {
+	Name          string
+	Description   string
+	Profiles      []string
+	UserData      Multipart
+	VendorData    Multipart
+	NetworkConfig Renderer
+}
+
+func (c *LXDConfig) Config() (map[string]string, error) {
+	config := make(map[string]string, 3)
+	for _, item := range []struct {
+		key string
+		r   Renderer
+	}{
+		{key: lxdUserData, r: c.UserData},
+		{key: lxdVendorData, r: c.VendorData},
+		{key: lxdNetworkConfig, r: c.NetworkConfig},
+	} {
+		if item.r == nil {
+			continue
+		}
+
+		b, err := renderBytes(item.r)
+		if err != nil {
+			logger.Println("failed to build lxd config", "func", getFuncName(), "key", item.key, "error", err)
+			return nil, err
+		}
+
+		config[item.key] = string(b)
+	}
+
+	return config, nil
+}
+
+func (c *LXDConfig) RenderProfile(w io.Writer) error {
+	config, err := c.Config()
+	if err != nil {
+		logger.Println("failed to render lxd profile", "func", getFuncName(), "name", c.Name, "error", err)
+		return err
+	}
+
+	// values without CR render as literal blocks, values with CR fall back to double-quoted scalars to keep CRLF
+	doc := struct {
+		Name        string            `yaml:"name,omitempty"`
+		Description string            `yaml:"description"`
+		Config      map[string]string `yaml:"config"`
+		Devices     map[string]any    `yaml:"devices"`
+	}{
+		Name:        c.Name,
+		Description: c.Description,
+		Config:      config,
+		Devices:     map[string]any{},
+	}
+
+	if err := renderYAML(w, &doc); err != nil {
+		logger.Println("failed to render lxd profile", "func", getFuncName(), "name", c.Name, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *LXDConfig) RenderInstance(w io.Writer) error {
+	config, err := c.Config()
+	if err != nil {
+		logger.Println("failed to render lxd instance", "func", getFuncName(), "name", c.Name, "error", err)
+		return err
+	}
+
+	doc := struct {
+		Description string            `yaml:"description,omitempty"`
+		Config      map[string]string `yaml:"config"`
+		Devices     map[string]any    `yaml:"devices"`
+		Profiles    []string          `yaml:"profiles,omitempty"`
+	}{
+		Description: c.Description,
+		Config:      config,
+		Devices:     map[string]any{},
+		Profiles:    c.Profiles,
+	}
+
+	if err := renderYAML(w, &doc); err != nil {
+		logger.Println("failed to render lxd instance", "func", getFuncName(), "name", c.Name, "error", err)
+		return err
+	}
+
+	return nil
+}
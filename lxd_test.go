@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLXDConfig_RenderProfile(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		config   LXDConfig
+		expected expected
+	}{
+		{
+			name: "positive case",
+			config: LXDConfig{
+				Name:        "cloud",
+				Description: "cloud-init profile",
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					return m
+				}(),
+				NetworkConfig: &NetworkConfigV2{
+					Ethernets: map[string]NetworkV2Ethernet{
+						"eth0": {NetworkV2Device: NetworkV2Device{DHCP4: true}},
+					},
+				},
+			},
+			expected: expected{
+				res: "name: cloud\n" +
+					"description: cloud-init profile\n" +
+					"config:\n" +
+					"  cloud-init.network-config: |\n" +
+					"    version: 2\n" +
+					"    ethernets:\n" +
+					"      eth0:\n" +
+					"        dhcp4: true\n" +
+					"  cloud-init.user-data: \"Content-Type: multipart/mixed; boundary=\\\"+Go+User+Data+Boundary==\\\"\\r\\nMime-Version: 1.0\\r\\n\\r\\n--+Go+User+Data+Boundary==--\\r\\n\"\n" +
+					"devices: {}\n",
+			},
+		},
+		{
+			name: "positive case: empty",
+			config: LXDConfig{
+				Name: "empty",
+			},
+			expected: expected{
+				res: "name: empty\n" +
+					"description: \"\"\n" +
+					"config: {}\n" +
+					"devices: {}\n",
+			},
+		},
+		{
+			name: "negative case: invalid network config",
+			config: LXDConfig{
+				NetworkConfig: &NetworkConfigV2{
+					VLANs: map[string]NetworkV2VLAN{"vlan100": {ID: 100, Link: "eth0"}},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidReference},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.config.RenderProfile(buf)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, buf.String())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestLXDConfig_RenderInstance(t *testing.T) {
+	m, _ := NewMultipart()
+	m.Append(mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\n"+"echo hello\n")))
+	m.Append(mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/sh\r\n"+"echo crlf\r\n")))
+
+	c := &LXDConfig{
+		Profiles:   []string{"default", "cloud"},
+		UserData:   m,
+		VendorData: m,
+		NetworkConfig: &NetworkConfigV2{
+			Ethernets: map[string]NetworkV2Ethernet{
+				"eth0": {NetworkV2Device: NetworkV2Device{DHCP4: true}},
+			},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, c.RenderInstance(buf))
+
+	var doc struct {
+		Config   map[string]string `yaml:"config"`
+		Profiles []string          `yaml:"profiles"`
+	}
+	assert.NoError(t, yaml.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Contains(t, buf.String(), "  cloud-init.network-config: |\n")
+	assert.Contains(t, buf.String(), "  cloud-init.user-data: \"Content-Type: multipart/mixed;")
+
+	config, err := c.Config()
+	assert.NoError(t, err)
+	assert.Equal(t, config, doc.Config)
+
+	expected := new(bytes.Buffer)
+	assert.NoError(t, m.Render(expected))
+	assert.Equal(t, expected.String(), doc.Config["cloud-init.user-data"])
+	assert.Equal(t, []string{"default", "cloud"}, doc.Profiles)
+}
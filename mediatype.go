@@ -0,0 +1,278 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"mime"
+	"sync"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type Stage string
+
+const (
+	StageInitLocal Stage = "init-local"
+	StageInit      Stage = "init"
+	StageConfig    Stage = "config"
+	StageFinal     Stage = "final"
+)
+
+type MediaTypeSpec struct {
+	MediaType   MediaType
+	Marker      string
+	Validator   func(body []byte) error
+	Encoding    Encoding
+	Description string
+	Stage       Stage
+	Frequency   Frequency
+	Script      bool
+}
+
+var (
+	builtinMediaTypeSpecs = map[MediaType]MediaTypeSpec{
+		MediaTypeCloudBoothook: {
+			MediaType:   MediaTypeCloudBoothook,
+			Marker:      "#cloud-boothook",
+			Description: "cloud-init boothook run early on every boot",
+			Stage:       StageInit,
+			Frequency:   FrequencyAlways,
+		},
+		MediaTypeCloudConfig: {
+			MediaType:   MediaTypeCloudConfig,
+			Marker:      "#cloud-config",
+			Description: "cloud-config YAML document",
+			Stage:       StageConfig,
+		},
+		MediaTypeCloudConfigArchive: {
+			MediaType:   MediaTypeCloudConfigArchive,
+			Marker:      "#cloud-config-archive",
+			Description: "list of parts in YAML",
+			Stage:       StageConfig,
+		},
+		MediaTypeCloudConfigJsonp: {
+			MediaType:   MediaTypeCloudConfigJsonp,
+			Marker:      "#cloud-config-jsonp",
+			Description: "JSON patch applied to the merged cloud-config",
+			Stage:       StageConfig,
+		},
+		MediaTypeJinja2: {
+			MediaType:   MediaTypeJinja2,
+			Marker:      "## template: jinja",
+			Description: "Jinja2 template rendered with instance data",
+			Stage:       StageInit,
+		},
+		MediaTypePartHandler: {
+			MediaType:   MediaTypePartHandler,
+			Marker:      "#part-handler",
+			Description: "Python part-handler module",
+			Stage:       StageInit,
+			Frequency:   FrequencyPerInstance,
+		},
+		MediaTypeXIncludeOnceUrl: {
+			MediaType:   MediaTypeXIncludeOnceUrl,
+			Marker:      "#include-once",
+			Description: "list of URLs fetched once per instance",
+			Stage:       StageInit,
+		},
+		MediaTypeXIncludeUrl: {
+			MediaType:   MediaTypeXIncludeUrl,
+			Marker:      "#include",
+			Description: "list of URLs fetched on every boot",
+			Stage:       StageInit,
+		},
+		MediaTypeXShellscript: {
+			MediaType:   MediaTypeXShellscript,
+			Marker:      "#!",
+			Description: "shell script run once per instance by the scripts-user module",
+			Stage:       StageFinal,
+			Frequency:   FrequencyPerInstance,
+			Script:      true,
+		},
+		MediaTypeXShellscriptPerBoot: {
+			MediaType:   MediaTypeXShellscriptPerBoot,
+			Marker:      "#!",
+			Description: "shell script run on every boot",
+			Stage:       StageFinal,
+			Frequency:   FrequencyPerBoot,
+			Script:      true,
+		},
+		MediaTypeXShellscriptPerInstance: {
+			MediaType:   MediaTypeXShellscriptPerInstance,
+			Marker:      "#!",
+			Description: "shell script run once per instance",
+			Stage:       StageFinal,
+			Frequency:   FrequencyPerInstance,
+			Script:      true,
+		},
+		MediaTypeXShellscriptPerOnce: {
+			MediaType:   MediaTypeXShellscriptPerOnce,
+			Marker:      "#!",
+			Description: "shell script run once",
+			Stage:       StageFinal,
+			Frequency:   FrequencyOnce,
+			Script:      true,
+		},
+		MediaTypeNodeEKSAWS: {
+			MediaType:   MediaTypeNodeEKSAWS,
+			Description: "EKS nodeadm NodeConfig document",
+		},
+	}
+
+	registeredMediaTypeSpecs = make(map[MediaType]MediaTypeSpec)
+	mediatypemu              sync.RWMutex
+)
+
+func RegisterMediaType(spec MediaTypeSpec) error {
+	mediatypemu.Lock()
+	defer mediatypemu.Unlock()
+
+	typ, params, err := mime.ParseMediaType(string(spec.MediaType))
+	if err != nil || len(params) > 0 || typ != string(spec.MediaType) {
+		err := &Error{Op: "register", Err: ErrInvalidMediaType}
+		logger.Println("failed to register media type", "func", getFuncName(), "spec", spec, "error", err)
+		return err
+	}
+
+	if slices.Contains(builtinMediaTypes, spec.MediaType) {
+		err := &Error{Op: "register", Err: ErrReservedMediaType}
+		logger.Println("failed to register media type", "func", getFuncName(), "spec", spec, "error", err)
+		return err
+	}
+
+	switch spec.Encoding {
+	case "", Encoding7bit, EncodingBase64:
+	default:
+		err := &Error{Op: "register", Err: ErrInvalidEncoding}
+		logger.Println("failed to register media type", "func", getFuncName(), "spec", spec, "error", err)
+		return err
+	}
+
+	if _, ok := registeredMediaTypeSpecs[spec.MediaType]; ok {
+		err := &Error{Op: "register", Err: ErrDuplicateMediaType}
+		logger.Println("failed to register media type", "func", getFuncName(), "spec", spec, "error", err)
+		return err
+	}
+
+	registeredMediaTypeSpecs[spec.MediaType] = spec
+
+	return nil
+}
+
+func LookupMediaType(mediaType MediaType) (MediaTypeSpec, bool) {
+	return lookupMediaType(mediaType)
+}
+
+func DetectMediaType(body []byte) (MediaType, error) {
+	var detected MediaTypeSpec
+	for _, spec := range mediaTypeSpecs() {
+		if spec.Marker == "" || len(spec.Marker) <= len(detected.Marker) {
+			continue
+		}
+
+		if bytes.HasPrefix(body, []byte(spec.Marker)) {
+			detected = spec
+		}
+	}
+
+	if detected.MediaType == "" {
+		err := &Error{Op: "detect", Err: ErrUnknownMediaType}
+		logger.Println("failed to detect media type", "func", getFuncName(), "error", err)
+		return "", err
+	}
+
+	return detected.MediaType, nil
+}
+
+func ParseMediaType(s string) (MediaType, error) {
+	typ, _, err := mime.ParseMediaType(s)
+	if err != nil {
+		err := &Error{Op: "parse", Err: ErrInvalidMediaType}
+		logger.Println("failed to parse media type", "func", getFuncName(), "mediaType", s, "error", err)
+		return "", err
+	}
+
+	mt := MediaType(typ)
+	if !mt.Valid() {
+		err := &Error{Op: "parse", Err: ErrUnknownMediaType}
+		logger.Println("failed to parse media type", "func", getFuncName(), "mediaType", s, "error", err)
+		return "", err
+	}
+
+	return mt, nil
+}
+
+func (t MediaType) Valid() bool {
+	_, ok := lookupMediaType(t)
+	return ok
+}
+
+func (t MediaType) IsScript() bool {
+	spec, _ := lookupMediaType(t)
+	return spec.Script
+}
+
+func (t MediaType) Frequency() Frequency {
+	spec, _ := lookupMediaType(t)
+	return spec.Frequency
+}
+
+func (t MediaType) Stage() Stage {
+	spec, _ := lookupMediaType(t)
+	return spec.Stage
+}
+
+func (t MediaType) Marker() string {
+	spec, _ := lookupMediaType(t)
+	return spec.Marker
+}
+
+func mediaTypeSpecs() []MediaTypeSpec {
+	specs := make([]MediaTypeSpec, 0, len(builtinMediaTypes))
+	for _, mt := range builtinMediaTypes {
+		specs = append(specs, builtinMediaTypeSpecs[mt])
+	}
+
+	mediatypemu.RLock()
+	defer mediatypemu.RUnlock()
+
+	registered := maps.Keys(registeredMediaTypeSpecs)
+	slices.Sort(registered)
+	for _, mt := range registered {
+		specs = append(specs, registeredMediaTypeSpecs[mt])
+	}
+
+	return specs
+}
+
+func lookupMediaType(t MediaType) (MediaTypeSpec, bool) {
+	if spec, ok := builtinMediaTypeSpecs[t]; ok {
+		return spec, true
+	}
+
+	mediatypemu.RLock()
+	defer mediatypemu.RUnlock()
+
+	spec, ok := registeredMediaTypeSpecs[t]
+	return spec, ok
+}
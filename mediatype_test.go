@@ -0,0 +1,349 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func unregisterMediaType(mediaType MediaType) {
+	mediatypemu.Lock()
+	defer mediatypemu.Unlock()
+
+	delete(registeredMediaTypeSpecs, mediaType)
+}
+
+func TestRegisterMediaType(t *testing.T) {
+	type args struct {
+		spec MediaTypeSpec
+	}
+
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     []args
+		expected expected
+	}{
+		{
+			name: "positive case",
+			args: []args{
+				{spec: MediaTypeSpec{MediaType: "text/x-acme-bootstrap", Marker: "#acme", Stage: StageFinal}},
+			},
+			expected: expected{
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: invalid media type",
+			args: []args{
+				{spec: MediaTypeSpec{MediaType: "text/x-acme-bootstrap; charset=us-ascii"}},
+			},
+			expected: expected{
+				err: &Error{Op: "register", Err: ErrInvalidMediaType},
+			},
+		},
+		{
+			name: "negative case: builtin media type",
+			args: []args{
+				{spec: MediaTypeSpec{MediaType: MediaTypeCloudConfig}},
+			},
+			expected: expected{
+				err: &Error{Op: "register", Err: ErrReservedMediaType},
+			},
+		},
+		{
+			name: "negative case: already registered",
+			args: []args{
+				{spec: MediaTypeSpec{MediaType: "text/x-acme-bootstrap"}},
+				{spec: MediaTypeSpec{MediaType: "text/x-acme-bootstrap"}},
+			},
+			expected: expected{
+				err: &Error{Op: "register", Err: ErrDuplicateMediaType},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			for _, args := range tt.args {
+				mediaType := args.spec.MediaType
+				t.Cleanup(func() { unregisterMediaType(mediaType) })
+				err = RegisterMediaType(args.spec)
+			}
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestParseMediaType(t *testing.T) {
+	type args struct {
+		s string
+	}
+
+	type expected struct {
+		res MediaType
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case",
+			args: args{
+				s: "text/cloud-config",
+			},
+			expected: expected{
+				res: MediaTypeCloudConfig,
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: with parameters",
+			args: args{
+				s: "Text/X-Shellscript; charset=us-ascii",
+			},
+			expected: expected{
+				res: MediaTypeXShellscript,
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: malformed",
+			args: args{
+				s: "text/cloud-config;;",
+			},
+			expected: expected{
+				err: &Error{Op: "parse", Err: ErrInvalidMediaType},
+			},
+		},
+		{
+			name: "negative case: typo",
+			args: args{
+				s: "text/cloud-conifg",
+			},
+			expected: expected{
+				err: &Error{Op: "parse", Err: ErrUnknownMediaType},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseMediaType(tt.args.s)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestMediaType(t *testing.T) {
+	type expected struct {
+		valid     bool
+		script    bool
+		frequency Frequency
+		stage     Stage
+		marker    string
+	}
+
+	tests := []struct {
+		name      string
+		mediaType MediaType
+		expected  expected
+	}{
+		{
+			name:      "positive case: cloud-config",
+			mediaType: MediaTypeCloudConfig,
+			expected: expected{
+				valid:  true,
+				stage:  StageConfig,
+				marker: "#cloud-config",
+			},
+		},
+		{
+			name:      "positive case: boothook",
+			mediaType: MediaTypeCloudBoothook,
+			expected: expected{
+				valid:     true,
+				frequency: FrequencyAlways,
+				stage:     StageInit,
+				marker:    "#cloud-boothook",
+			},
+		},
+		{
+			name:      "positive case: jinja2",
+			mediaType: MediaTypeJinja2,
+			expected: expected{
+				valid:  true,
+				stage:  StageInit,
+				marker: "## template: jinja",
+			},
+		},
+		{
+			name:      "positive case: script",
+			mediaType: MediaTypeXShellscript,
+			expected: expected{
+				valid:     true,
+				script:    true,
+				frequency: FrequencyPerInstance,
+				stage:     StageFinal,
+				marker:    "#!",
+			},
+		},
+		{
+			name:      "positive case: per-once script",
+			mediaType: MediaTypeXShellscriptPerOnce,
+			expected: expected{
+				valid:     true,
+				script:    true,
+				frequency: FrequencyOnce,
+				stage:     StageFinal,
+				marker:    "#!",
+			},
+		},
+		{
+			name:      "negative case: unknown",
+			mediaType: "text/cloud-conifg",
+			expected: expected{
+				valid: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected.valid, tt.mediaType.Valid())
+			assert.Equal(t, tt.expected.script, tt.mediaType.IsScript())
+			assert.Equal(t, tt.expected.frequency, tt.mediaType.Frequency())
+			assert.Equal(t, tt.expected.stage, tt.mediaType.Stage())
+			assert.Equal(t, tt.expected.marker, tt.mediaType.Marker())
+		})
+	}
+}
+
+func TestDetectMediaType(t *testing.T) {
+	type args struct {
+		body []byte
+	}
+
+	type expected struct {
+		res MediaType
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: cloud-config",
+			args: args{
+				body: []byte("#cloud-config\n" + "timezone: Europe/London"),
+			},
+			expected: expected{
+				res: MediaTypeCloudConfig,
+			},
+		},
+		{
+			name: "positive case: longest marker",
+			args: args{
+				body: []byte("#cloud-config-archive\n" + "- type: text/cloud-config"),
+			},
+			expected: expected{
+				res: MediaTypeCloudConfigArchive,
+			},
+		},
+		{
+			name: "positive case: include once",
+			args: args{
+				body: []byte("#include-once\n" + "https://example.com/user-data"),
+			},
+			expected: expected{
+				res: MediaTypeXIncludeOnceUrl,
+			},
+		},
+		{
+			name: "positive case: shell script",
+			args: args{
+				body: []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+			},
+			expected: expected{
+				res: MediaTypeXShellscript,
+			},
+		},
+		{
+			name: "positive case: registered",
+			args: args{
+				body: []byte("#acme-bootstrap\n" + "bootstrap: true"),
+			},
+			expected: expected{
+				res: "text/x-acme-bootstrap",
+			},
+		},
+		{
+			name: "negative case: unknown",
+			args: args{
+				body: []byte("timezone: Europe/London"),
+			},
+			expected: expected{
+				err: &Error{Op: "detect", Err: ErrUnknownMediaType},
+			},
+		},
+	}
+
+	assert.NoError(t, RegisterMediaType(MediaTypeSpec{MediaType: "text/x-acme-bootstrap", Marker: "#acme-bootstrap"}))
+	t.Cleanup(func() { unregisterMediaType("text/x-acme-bootstrap") })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := DetectMediaType(tt.args.body)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -25,6 +25,8 @@ import (
 	"io"
 	"mime"
 	"regexp"
+
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -38,6 +40,7 @@ var (
 
 type Multipart interface {
 	Append(part Part)
+	Parts() []Part
 	Renderer
 }
 
@@ -75,6 +78,10 @@ func (m *multipart) Append(part Part) {
 	m.parts = append(m.parts, part)
 }
 
+func (m *multipart) Parts() []Part {
+	return slices.Clone(m.parts)
+}
+
 func (m *multipart) Render(w io.Writer) error {
 	if err := m.header.Render(w); err != nil {
 		logger.Println("failed to render multipart", "func", getFuncName(), "multipart", m, "error", err)
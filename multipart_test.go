@@ -278,10 +278,10 @@ func TestMultipart_Append(t *testing.T) {
 			}(),
 			args: []args{
 				{
-					part: NewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London")),
+					part: mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London")),
 				},
 				{
-					part: NewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'Hello World'")),
+					part: mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'Hello World'")),
 				},
 			},
 			expected: expected{
@@ -300,7 +300,8 @@ func TestMultipart_Append(t *testing.T) {
 									"Content-Type":              {"text/cloud-config; charset=us-ascii"},
 								},
 							},
-							body: []byte("#cloud-config\n" + "timezone: Europe/London"),
+							body:      []byte("#cloud-config\n" + "timezone: Europe/London"),
+							mediaType: MediaTypeCloudConfig,
 						},
 						&part{
 							header: &header{
@@ -309,7 +310,8 @@ func TestMultipart_Append(t *testing.T) {
 									"Content-Type":              {"text/x-shellscript; charset=us-ascii"},
 								},
 							},
-							body: []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+							body:      []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+							mediaType: MediaTypeXShellscript,
 						},
 					},
 					boundary: "+Go+User+Data+Boundary==",
@@ -324,10 +326,10 @@ func TestMultipart_Append(t *testing.T) {
 			}(),
 			args: []args{
 				{
-					part: NewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Asia/Tokyo")),
+					part: mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Asia/Tokyo")),
 				},
 				{
-					part: NewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'こんにちは世界'")),
+					part: mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'こんにちは世界'")),
 				},
 			},
 			expected: expected{
@@ -346,7 +348,8 @@ func TestMultipart_Append(t *testing.T) {
 									"Content-Type":              {"text/cloud-config; charset=us-ascii"},
 								},
 							},
-							body: []byte("#cloud-config\n" + "timezone: Asia/Tokyo"),
+							body:      []byte("#cloud-config\n" + "timezone: Asia/Tokyo"),
+							mediaType: MediaTypeCloudConfig,
 						},
 						&part{
 							header: &header{
@@ -359,6 +362,7 @@ func TestMultipart_Append(t *testing.T) {
 								// base64.StdEncoding.EncodeToString([]byte("#!/bin/bash\n" + "echo 'こんにちは世界'")),
 								"IyEvYmluL2Jhc2gKZWNobyAn44GT44KT44Gr44Gh44Gv5LiW55WMJw==",
 							),
+							mediaType: MediaTypeXShellscript,
 						},
 					},
 					boundary: "+Go+User+Data+Boundary==",
@@ -378,6 +382,21 @@ func TestMultipart_Append(t *testing.T) {
 	}
 }
 
+func TestMultipart_Parts(t *testing.T) {
+	cfg := mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London"))
+	scr := mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'Hello World'"))
+
+	m, _ := NewMultipart()
+	m.Append(cfg)
+	m.Append(scr)
+
+	parts := m.Parts()
+	assert.Equal(t, []Part{cfg, scr}, parts)
+
+	parts[0] = nil
+	assert.Equal(t, []Part{cfg, scr}, m.Parts())
+}
+
 func TestMultipart_Render(t *testing.T) {
 	type expected struct {
 		res string
@@ -394,8 +413,8 @@ func TestMultipart_Render(t *testing.T) {
 			multipart: func() Multipart {
 				m, _ := NewMultipart()
 
-				m.Append(NewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London")))
-				m.Append(NewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'Hello World'")))
+				m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London")))
+				m.Append(mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'Hello World'")))
 
 				return m
 			}(),
@@ -426,8 +445,8 @@ func TestMultipart_Render(t *testing.T) {
 			multipart: func() Multipart {
 				m, _ := NewMultipart()
 
-				m.Append(NewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Asia/Tokyo")))
-				m.Append(NewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'こんにちは世界'")))
+				m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Asia/Tokyo")))
+				m.Append(mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'こんにちは世界'")))
 
 				return m
 			}(),
@@ -0,0 +1,515 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"io"
+	"net"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	NetworkV1TypePhysical   = "physical"
+	NetworkV1TypeBond       = "bond"
+	NetworkV1TypeBridge     = "bridge"
+	NetworkV1TypeVLAN       = "vlan"
+	NetworkV1TypeNameserver = "nameserver"
+	NetworkV1TypeRoute      = "route"
+)
+
+const (
+	NetworkV1SubnetStatic     = "static"
+	NetworkV1SubnetStatic6    = "static6"
+	NetworkV1SubnetDHCP       = "dhcp"
+	NetworkV1SubnetDHCP4      = "dhcp4"
+	NetworkV1SubnetDHCP6      = "dhcp6"
+	NetworkV1SubnetIPv6DHCPv6 = "ipv6_dhcpv6-stateless"
+	NetworkV1SubnetIPv6SLAAC  = "ipv6_slaac"
+	NetworkV1SubnetManual     = "manual"
+)
+
+const (
+	minVLANID = 1
+	maxVLANID = 4094
+)
+
+var (
+	networkV1Types = []string{
+		NetworkV1TypePhysical,
+		NetworkV1TypeBond,
+		NetworkV1TypeBridge,
+		NetworkV1TypeVLAN,
+		NetworkV1TypeNameserver,
+		NetworkV1TypeRoute,
+	}
+
+	networkV1SubnetTypes = []string{
+		NetworkV1SubnetStatic,
+		NetworkV1SubnetStatic6,
+		NetworkV1SubnetDHCP,
+		NetworkV1SubnetDHCP4,
+		NetworkV1SubnetDHCP6,
+		NetworkV1SubnetIPv6DHCPv6,
+		NetworkV1SubnetIPv6SLAAC,
+		NetworkV1SubnetManual,
+	}
+)
+
+type NetworkConfigV1 struct {
+	Config []NetworkV1Config `yaml:"config"`
+}
+
+type NetworkV1Config struct {
+	Type             string            `yaml:"type"`
+	Name             string            `yaml:"name,omitempty"`
+	MACAddress       string            `yaml:"mac_address,omitempty"`
+	MTU              int               `yaml:"mtu,omitempty"`
+	BondInterfaces   []string          `yaml:"bond_interfaces,omitempty"`
+	BridgeInterfaces []string          `yaml:"bridge_interfaces,omitempty"`
+	VLANLink         string            `yaml:"vlan_link,omitempty"`
+	VLANID           int               `yaml:"vlan_id,omitempty"`
+	Params           map[string]any    `yaml:"params,omitempty"`
+	Subnets          []NetworkV1Subnet `yaml:"subnets,omitempty"`
+	Address          []string          `yaml:"address,omitempty"`
+	Search           []string          `yaml:"search,omitempty"`
+	Interface        string            `yaml:"interface,omitempty"`
+	Destination      string            `yaml:"destination,omitempty"`
+	Gateway          string            `yaml:"gateway,omitempty"`
+	Metric           int               `yaml:"metric,omitempty"`
+}
+
+type NetworkV1Subnet struct {
+	Type           string           `yaml:"type"`
+	Address        string           `yaml:"address,omitempty"`
+	Netmask        string           `yaml:"netmask,omitempty"`
+	Gateway        string           `yaml:"gateway,omitempty"`
+	DNSNameservers []string         `yaml:"dns_nameservers,omitempty"`
+	DNSSearch      []string         `yaml:"dns_search,omitempty"`
+	Routes         []NetworkV1Route `yaml:"routes,omitempty"`
+}
+
+type NetworkV1Route struct {
+	Network string `yaml:"network"`
+	Netmask string `yaml:"netmask,omitempty"`
+	Gateway string `yaml:"gateway"`
+	Metric  int    `yaml:"metric,omitempty"`
+}
+
+func (c *NetworkConfigV1) Render(w io.Writer) error {
+	if err := c.validate(); err != nil {
+		logger.Println("failed to render network config", "func", getFuncName(), "networkConfig", c, "error", err)
+		return err
+	}
+
+	doc := struct {
+		Version          int `yaml:"version"`
+		*NetworkConfigV1 `yaml:",inline"`
+	}{Version: 1, NetworkConfigV1: c}
+
+	if err := renderYAML(w, &doc); err != nil {
+		logger.Println("failed to render network config", "func", getFuncName(), "networkConfig", c, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *NetworkConfigV1) validate() error {
+	names := make([]string, 0, len(c.Config))
+	for _, cfg := range c.Config {
+		if cfg.Name == "" {
+			continue
+		}
+
+		if slices.Contains(names, cfg.Name) {
+			return &Error{Op: "validate", Err: ErrInvalidName}
+		}
+
+		names = append(names, cfg.Name)
+	}
+
+	for _, cfg := range c.Config {
+		if !slices.Contains(networkV1Types, cfg.Type) {
+			return &Error{Op: "validate", Err: ErrInvalidType}
+		}
+
+		switch cfg.Type {
+		case NetworkV1TypePhysical, NetworkV1TypeBond, NetworkV1TypeBridge, NetworkV1TypeVLAN:
+			if cfg.Name == "" {
+				return &Error{Op: "validate", Err: ErrInvalidName}
+			}
+		}
+
+		if cfg.MACAddress != "" && !validMAC(cfg.MACAddress) {
+			return &Error{Op: "validate", Err: ErrInvalidMACAddress}
+		}
+
+		refs := append(append([]string{}, cfg.BondInterfaces...), cfg.BridgeInterfaces...)
+		if cfg.VLANLink != "" {
+			refs = append(refs, cfg.VLANLink)
+		}
+		if cfg.Interface != "" {
+			refs = append(refs, cfg.Interface)
+		}
+		for _, ref := range refs {
+			if ref == cfg.Name || !slices.Contains(names, ref) {
+				return &Error{Op: "validate", Err: ErrInvalidReference}
+			}
+		}
+
+		if cfg.Type == NetworkV1TypeVLAN {
+			if cfg.VLANLink == "" {
+				return &Error{Op: "validate", Err: ErrInvalidReference}
+			}
+
+			if cfg.VLANID < minVLANID || cfg.VLANID > maxVLANID {
+				return &Error{Op: "validate", Err: ErrInvalidVLANID}
+			}
+		}
+
+		for _, addr := range cfg.Address {
+			if !validIP(addr) {
+				return &Error{Op: "validate", Err: ErrInvalidIPAddress}
+			}
+		}
+
+		if cfg.Destination != "" && !validCIDR(cfg.Destination) {
+			return &Error{Op: "validate", Err: ErrInvalidCIDR}
+		}
+
+		if cfg.Gateway != "" && !validIP(cfg.Gateway) {
+			return &Error{Op: "validate", Err: ErrInvalidIPAddress}
+		}
+
+		for _, subnet := range cfg.Subnets {
+			if err := subnet.validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *NetworkV1Subnet) validate() error {
+	if !slices.Contains(networkV1SubnetTypes, s.Type) {
+		return &Error{Op: "validate", Err: ErrInvalidType}
+	}
+
+	switch s.Type {
+	case NetworkV1SubnetStatic, NetworkV1SubnetStatic6:
+		if s.Address == "" {
+			return &Error{Op: "validate", Err: ErrInvalidCIDR}
+		}
+	}
+
+	// v1 accepts either a cidr or a bare address paired with a netmask
+	if s.Address != "" && !validCIDR(s.Address) && !(validIP(s.Address) && s.Netmask != "") {
+		return &Error{Op: "validate", Err: ErrInvalidCIDR}
+	}
+
+	if s.Netmask != "" && !validIP(s.Netmask) {
+		return &Error{Op: "validate", Err: ErrInvalidIPAddress}
+	}
+
+	if s.Gateway != "" && !validIP(s.Gateway) {
+		return &Error{Op: "validate", Err: ErrInvalidIPAddress}
+	}
+
+	for _, addr := range s.DNSNameservers {
+		if !validIP(addr) {
+			return &Error{Op: "validate", Err: ErrInvalidIPAddress}
+		}
+	}
+
+	for _, route := range s.Routes {
+		if !validCIDR(route.Network) && !(validIP(route.Network) && route.Netmask != "") {
+			return &Error{Op: "validate", Err: ErrInvalidCIDR}
+		}
+
+		if route.Netmask != "" && !validIP(route.Netmask) {
+			return &Error{Op: "validate", Err: ErrInvalidIPAddress}
+		}
+
+		if !validIP(route.Gateway) {
+			return &Error{Op: "validate", Err: ErrInvalidIPAddress}
+		}
+	}
+
+	return nil
+}
+
+type NetworkConfigV2 struct {
+	Ethernets map[string]NetworkV2Ethernet `yaml:"ethernets,omitempty"`
+	Bonds     map[string]NetworkV2Bond     `yaml:"bonds,omitempty"`
+	Bridges   map[string]NetworkV2Bridge   `yaml:"bridges,omitempty"`
+	VLANs     map[string]NetworkV2VLAN     `yaml:"vlans,omitempty"`
+}
+
+type NetworkV2Device struct {
+	DHCP4          bool                    `yaml:"dhcp4,omitempty"`
+	DHCP6          bool                    `yaml:"dhcp6,omitempty"`
+	DHCP4Overrides *NetworkV2DHCPOverrides `yaml:"dhcp4-overrides,omitempty"`
+	DHCP6Overrides *NetworkV2DHCPOverrides `yaml:"dhcp6-overrides,omitempty"`
+	Addresses      []string                `yaml:"addresses,omitempty"`
+	Gateway4       string                  `yaml:"gateway4,omitempty"`
+	Gateway6       string                  `yaml:"gateway6,omitempty"`
+	MTU            int                     `yaml:"mtu,omitempty"`
+	MACAddress     string                  `yaml:"macaddress,omitempty"`
+	Nameservers    *NetworkV2Nameservers   `yaml:"nameservers,omitempty"`
+	Routes         []NetworkV2Route        `yaml:"routes,omitempty"`
+}
+
+type NetworkV2Ethernet struct {
+	Match           *NetworkV2Match `yaml:"match,omitempty"`
+	SetName         string          `yaml:"set-name,omitempty"`
+	WakeOnLAN       bool            `yaml:"wakeonlan,omitempty"`
+	NetworkV2Device `yaml:",inline"`
+}
+
+type NetworkV2Match struct {
+	Name       string `yaml:"name,omitempty"`
+	MACAddress string `yaml:"macaddress,omitempty"`
+	Driver     string `yaml:"driver,omitempty"`
+}
+
+type NetworkV2Bond struct {
+	Interfaces      []string                 `yaml:"interfaces"`
+	Parameters      *NetworkV2BondParameters `yaml:"parameters,omitempty"`
+	NetworkV2Device `yaml:",inline"`
+}
+
+type NetworkV2BondParameters struct {
+	Mode               string `yaml:"mode,omitempty"`
+	MIIMonitorInterval int    `yaml:"mii-monitor-interval,omitempty"`
+	LACPRate           string `yaml:"lacp-rate,omitempty"`
+	TransmitHashPolicy string `yaml:"transmit-hash-policy,omitempty"`
+	Primary            string `yaml:"primary,omitempty"`
+}
+
+type NetworkV2Bridge struct {
+	Interfaces      []string                   `yaml:"interfaces"`
+	Parameters      *NetworkV2BridgeParameters `yaml:"parameters,omitempty"`
+	NetworkV2Device `yaml:",inline"`
+}
+
+type NetworkV2BridgeParameters struct {
+	STP          *bool `yaml:"stp,omitempty"`
+	ForwardDelay int   `yaml:"forward-delay,omitempty"`
+	Priority     int   `yaml:"priority,omitempty"`
+}
+
+type NetworkV2VLAN struct {
+	ID              int    `yaml:"id"`
+	Link            string `yaml:"link"`
+	NetworkV2Device `yaml:",inline"`
+}
+
+type NetworkV2DHCPOverrides struct {
+	UseDNS      *bool  `yaml:"use-dns,omitempty"`
+	UseHostname *bool  `yaml:"use-hostname,omitempty"`
+	UseRoutes   *bool  `yaml:"use-routes,omitempty"`
+	UseMTU      *bool  `yaml:"use-mtu,omitempty"`
+	RouteMetric int    `yaml:"route-metric,omitempty"`
+	Hostname    string `yaml:"hostname,omitempty"`
+}
+
+type NetworkV2Nameservers struct {
+	Addresses []string `yaml:"addresses,omitempty"`
+	Search    []string `yaml:"search,omitempty"`
+}
+
+type NetworkV2Route struct {
+	To     string `yaml:"to"`
+	Via    string `yaml:"via"`
+	Metric int    `yaml:"metric,omitempty"`
+	OnLink bool   `yaml:"on-link,omitempty"`
+}
+
+func (c *NetworkConfigV2) Render(w io.Writer) error {
+	if err := c.validate(); err != nil {
+		logger.Println("failed to render network config", "func", getFuncName(), "networkConfig", c, "error", err)
+		return err
+	}
+
+	doc := struct {
+		Version          int `yaml:"version"`
+		*NetworkConfigV2 `yaml:",inline"`
+	}{Version: 2, NetworkConfigV2: c}
+
+	if err := renderYAML(w, &doc); err != nil {
+		logger.Println("failed to render network config", "func", getFuncName(), "networkConfig", c, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *NetworkConfigV2) validate() error {
+	ids := make([]string, 0, len(c.Ethernets)+len(c.Bonds)+len(c.Bridges)+len(c.VLANs))
+	for _, keys := range [][]string{maps.Keys(c.Ethernets), maps.Keys(c.Bonds), maps.Keys(c.Bridges), maps.Keys(c.VLANs)} {
+		for _, id := range keys {
+			if id == "" || slices.Contains(ids, id) {
+				return &Error{Op: "validate", Err: ErrInvalidName}
+			}
+
+			ids = append(ids, id)
+		}
+	}
+
+	for _, eth := range c.Ethernets {
+		if eth.Match != nil && eth.Match.MACAddress != "" && !validMAC(eth.Match.MACAddress) {
+			return &Error{Op: "validate", Err: ErrInvalidMACAddress}
+		}
+
+		if err := eth.NetworkV2Device.validate(); err != nil {
+			return err
+		}
+	}
+
+	for id, bond := range c.Bonds {
+		for _, ref := range bond.Interfaces {
+			if _, ok := c.Ethernets[ref]; !ok {
+				return &Error{Op: "validate", Err: ErrInvalidReference}
+			}
+		}
+
+		if bond.Parameters != nil && bond.Parameters.Primary != "" && !slices.Contains(bond.Interfaces, bond.Parameters.Primary) {
+			return &Error{Op: "validate", Err: ErrInvalidReference}
+		}
+
+		if slices.Contains(bond.Interfaces, id) {
+			return &Error{Op: "validate", Err: ErrInvalidReference}
+		}
+
+		if err := bond.NetworkV2Device.validate(); err != nil {
+			return err
+		}
+	}
+
+	for id, bridge := range c.Bridges {
+		for _, ref := range bridge.Interfaces {
+			if ref == id || !slices.Contains(ids, ref) {
+				return &Error{Op: "validate", Err: ErrInvalidReference}
+			}
+		}
+
+		if err := bridge.NetworkV2Device.validate(); err != nil {
+			return err
+		}
+	}
+
+	for id, vlan := range c.VLANs {
+		if vlan.Link == id || !slices.Contains(ids, vlan.Link) {
+			return &Error{Op: "validate", Err: ErrInvalidReference}
+		}
+
+		if vlan.ID < minVLANID || vlan.ID > maxVLANID {
+			return &Error{Op: "validate", Err: ErrInvalidVLANID}
+		}
+
+		if err := vlan.NetworkV2Device.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *NetworkV2Device) validate() error {
+	for _, addr := range d.Addresses {
+		if !validCIDR(addr) {
+			return &Error{Op: "validate", Err: ErrInvalidCIDR}
+		}
+	}
+
+	for _, gw := range []string{d.Gateway4, d.Gateway6} {
+		if gw != "" && !validIP(gw) {
+			return &Error{Op: "validate", Err: ErrInvalidIPAddress}
+		}
+	}
+
+	if d.MACAddress != "" && !validMAC(d.MACAddress) {
+		return &Error{Op: "validate", Err: ErrInvalidMACAddress}
+	}
+
+	if d.Nameservers != nil {
+		for _, addr := range d.Nameservers.Addresses {
+			if !validIP(addr) {
+				return &Error{Op: "validate", Err: ErrInvalidIPAddress}
+			}
+		}
+	}
+
+	for _, route := range d.Routes {
+		if route.To != "default" && !validCIDR(route.To) {
+			return &Error{Op: "validate", Err: ErrInvalidCIDR}
+		}
+
+		if !validIP(route.Via) {
+			return &Error{Op: "validate", Err: ErrInvalidIPAddress}
+		}
+	}
+
+	return nil
+}
+
+func renderYAML(w io.Writer, v any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(v); err != nil {
+		return &Error{Op: "render", Err: err}
+	}
+
+	if err := enc.Close(); err != nil {
+		return &Error{Op: "render", Err: err}
+	}
+
+	return nil
+}
+
+func marshalYAML(v any) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := renderYAML(buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func validMAC(s string) bool {
+	hw, err := net.ParseMAC(s)
+	return err == nil && len(hw) == 6
+}
+
+func validIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+func validCIDR(s string) bool {
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
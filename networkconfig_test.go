@@ -0,0 +1,431 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkConfigV1_Render(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		config   NetworkConfigV1
+		expected expected
+	}{
+		{
+			name: "positive case: physical",
+			config: NetworkConfigV1{
+				Config: []NetworkV1Config{
+					{
+						Type:       NetworkV1TypePhysical,
+						Name:       "eth0",
+						MACAddress: "52:54:00:12:34:00",
+						Subnets: []NetworkV1Subnet{
+							{
+								Type:           NetworkV1SubnetStatic,
+								Address:        "192.168.1.10/24",
+								Gateway:        "192.168.1.1",
+								DNSNameservers: []string{"192.168.1.1"},
+							},
+						},
+					},
+					{
+						Type:    NetworkV1TypeNameserver,
+						Address: []string{"8.8.8.8"},
+						Search:  []string{"example.com"},
+					},
+				},
+			},
+			expected: expected{
+				res: "version: 1\n" +
+					"config:\n" +
+					"  - type: physical\n" +
+					"    name: eth0\n" +
+					"    mac_address: \"52:54:00:12:34:00\"\n" +
+					"    subnets:\n" +
+					"      - type: static\n" +
+					"        address: 192.168.1.10/24\n" +
+					"        gateway: 192.168.1.1\n" +
+					"        dns_nameservers:\n" +
+					"          - 192.168.1.1\n" +
+					"  - type: nameserver\n" +
+					"    address:\n" +
+					"      - 8.8.8.8\n" +
+					"    search:\n" +
+					"      - example.com\n",
+			},
+		},
+		{
+			name: "positive case: bond and vlan",
+			config: NetworkConfigV1{
+				Config: []NetworkV1Config{
+					{Type: NetworkV1TypePhysical, Name: "eth0"},
+					{Type: NetworkV1TypePhysical, Name: "eth1"},
+					{
+						Type:           NetworkV1TypeBond,
+						Name:           "bond0",
+						BondInterfaces: []string{"eth0", "eth1"},
+						Params:         map[string]any{"bond-mode": "active-backup"},
+					},
+					{
+						Type:     NetworkV1TypeVLAN,
+						Name:     "bond0.100",
+						VLANLink: "bond0",
+						VLANID:   100,
+						Subnets:  []NetworkV1Subnet{{Type: NetworkV1SubnetDHCP}},
+					},
+					{Type: NetworkV1TypeRoute, Destination: "10.0.0.0/8", Gateway: "192.168.1.254"},
+				},
+			},
+			expected: expected{
+				res: "version: 1\n" +
+					"config:\n" +
+					"  - type: physical\n" +
+					"    name: eth0\n" +
+					"  - type: physical\n" +
+					"    name: eth1\n" +
+					"  - type: bond\n" +
+					"    name: bond0\n" +
+					"    bond_interfaces:\n" +
+					"      - eth0\n" +
+					"      - eth1\n" +
+					"    params:\n" +
+					"      bond-mode: active-backup\n" +
+					"  - type: vlan\n" +
+					"    name: bond0.100\n" +
+					"    vlan_link: bond0\n" +
+					"    vlan_id: 100\n" +
+					"    subnets:\n" +
+					"      - type: dhcp\n" +
+					"  - type: route\n" +
+					"    destination: 10.0.0.0/8\n" +
+					"    gateway: 192.168.1.254\n",
+			},
+		},
+		{
+			name: "negative case: unknown type",
+			config: NetworkConfigV1{
+				Config: []NetworkV1Config{{Type: "wifi", Name: "wlan0"}},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidType},
+			},
+		},
+		{
+			name: "negative case: invalid mac",
+			config: NetworkConfigV1{
+				Config: []NetworkV1Config{{Type: NetworkV1TypePhysical, Name: "eth0", MACAddress: "52:54:00:12:34"}},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidMACAddress},
+			},
+		},
+		{
+			name: "negative case: unknown bond interface",
+			config: NetworkConfigV1{
+				Config: []NetworkV1Config{
+					{Type: NetworkV1TypePhysical, Name: "eth0"},
+					{Type: NetworkV1TypeBond, Name: "bond0", BondInterfaces: []string{"eth0", "eth1"}},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidReference},
+			},
+		},
+		{
+			name: "negative case: duplicate name",
+			config: NetworkConfigV1{
+				Config: []NetworkV1Config{
+					{Type: NetworkV1TypePhysical, Name: "eth0"},
+					{Type: NetworkV1TypePhysical, Name: "eth0"},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidName},
+			},
+		},
+		{
+			name: "negative case: vlan id out of range",
+			config: NetworkConfigV1{
+				Config: []NetworkV1Config{
+					{Type: NetworkV1TypePhysical, Name: "eth0"},
+					{Type: NetworkV1TypeVLAN, Name: "eth0.5000", VLANLink: "eth0", VLANID: 5000},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidVLANID},
+			},
+		},
+		{
+			name: "negative case: missing vlan id",
+			config: NetworkConfigV1{
+				Config: []NetworkV1Config{
+					{Type: NetworkV1TypePhysical, Name: "eth0"},
+					{Type: NetworkV1TypeVLAN, Name: "eth0.0", VLANLink: "eth0"},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidVLANID},
+			},
+		},
+		{
+			name: "negative case: static without address",
+			config: NetworkConfigV1{
+				Config: []NetworkV1Config{
+					{Type: NetworkV1TypePhysical, Name: "eth0", Subnets: []NetworkV1Subnet{{Type: NetworkV1SubnetStatic}}},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidCIDR},
+			},
+		},
+		{
+			name: "negative case: invalid route gateway",
+			config: NetworkConfigV1{
+				Config: []NetworkV1Config{{Type: NetworkV1TypeRoute, Destination: "10.0.0.0/8", Gateway: "gateway"}},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidIPAddress},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.config.Render(buf)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, buf.String())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestNetworkConfigV2_Render(t *testing.T) {
+	stp := false
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		config   NetworkConfigV2
+		expected expected
+	}{
+		{
+			name: "positive case: ethernet",
+			config: NetworkConfigV2{
+				Ethernets: map[string]NetworkV2Ethernet{
+					"id0": {
+						Match:   &NetworkV2Match{MACAddress: "52:54:00:12:34:00"},
+						SetName: "eth0",
+						NetworkV2Device: NetworkV2Device{
+							Addresses:   []string{"192.168.1.10/24", "2001:db8::10/64"},
+							Nameservers: &NetworkV2Nameservers{Addresses: []string{"192.168.1.1"}},
+							Routes:      []NetworkV2Route{{To: "default", Via: "192.168.1.1"}},
+						},
+					},
+				},
+			},
+			expected: expected{
+				res: "version: 2\n" +
+					"ethernets:\n" +
+					"  id0:\n" +
+					"    match:\n" +
+					"      macaddress: \"52:54:00:12:34:00\"\n" +
+					"    set-name: eth0\n" +
+					"    addresses:\n" +
+					"      - 192.168.1.10/24\n" +
+					"      - 2001:db8::10/64\n" +
+					"    nameservers:\n" +
+					"      addresses:\n" +
+					"        - 192.168.1.1\n" +
+					"    routes:\n" +
+					"      - to: default\n" +
+					"        via: 192.168.1.1\n",
+			},
+		},
+		{
+			name: "positive case: bond, bridge and vlan",
+			config: NetworkConfigV2{
+				Ethernets: map[string]NetworkV2Ethernet{
+					"eth0": {},
+					"eth1": {},
+				},
+				Bonds: map[string]NetworkV2Bond{
+					"bond0": {
+						Interfaces: []string{"eth0", "eth1"},
+						Parameters: &NetworkV2BondParameters{Mode: "active-backup", Primary: "eth0"},
+					},
+				},
+				Bridges: map[string]NetworkV2Bridge{
+					"br0": {
+						Interfaces:      []string{"bond0"},
+						Parameters:      &NetworkV2BridgeParameters{STP: &stp},
+						NetworkV2Device: NetworkV2Device{DHCP4: true},
+					},
+				},
+				VLANs: map[string]NetworkV2VLAN{
+					"vlan100": {ID: 100, Link: "br0"},
+				},
+			},
+			expected: expected{
+				res: "version: 2\n" +
+					"ethernets:\n" +
+					"  eth0: {}\n" +
+					"  eth1: {}\n" +
+					"bonds:\n" +
+					"  bond0:\n" +
+					"    interfaces:\n" +
+					"      - eth0\n" +
+					"      - eth1\n" +
+					"    parameters:\n" +
+					"      mode: active-backup\n" +
+					"      primary: eth0\n" +
+					"bridges:\n" +
+					"  br0:\n" +
+					"    interfaces:\n" +
+					"      - bond0\n" +
+					"    parameters:\n" +
+					"      stp: false\n" +
+					"    dhcp4: true\n" +
+					"vlans:\n" +
+					"  vlan100:\n" +
+					"    id: 100\n" +
+					"    link: br0\n",
+			},
+		},
+		{
+			name: "negative case: invalid cidr",
+			config: NetworkConfigV2{
+				Ethernets: map[string]NetworkV2Ethernet{
+					"eth0": {NetworkV2Device: NetworkV2Device{Addresses: []string{"192.168.1.10"}}},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidCIDR},
+			},
+		},
+		{
+			name: "negative case: invalid match mac",
+			config: NetworkConfigV2{
+				Ethernets: map[string]NetworkV2Ethernet{
+					"eth0": {Match: &NetworkV2Match{MACAddress: "52-54-00"}},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidMACAddress},
+			},
+		},
+		{
+			name: "negative case: duplicate id",
+			config: NetworkConfigV2{
+				Ethernets: map[string]NetworkV2Ethernet{"eth0": {}},
+				Bridges:   map[string]NetworkV2Bridge{"eth0": {}},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidName},
+			},
+		},
+		{
+			name: "negative case: unknown bond interface",
+			config: NetworkConfigV2{
+				Ethernets: map[string]NetworkV2Ethernet{"eth0": {}},
+				Bonds:     map[string]NetworkV2Bond{"bond0": {Interfaces: []string{"eth0", "eth1"}}},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidReference},
+			},
+		},
+		{
+			name: "negative case: primary outside bond",
+			config: NetworkConfigV2{
+				Ethernets: map[string]NetworkV2Ethernet{"eth0": {}, "eth1": {}},
+				Bonds: map[string]NetworkV2Bond{
+					"bond0": {Interfaces: []string{"eth0"}, Parameters: &NetworkV2BondParameters{Primary: "eth1"}},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidReference},
+			},
+		},
+		{
+			name: "negative case: unknown vlan link",
+			config: NetworkConfigV2{
+				VLANs: map[string]NetworkV2VLAN{"vlan100": {ID: 100, Link: "eth0"}},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidReference},
+			},
+		},
+		{
+			name: "negative case: missing vlan id",
+			config: NetworkConfigV2{
+				Ethernets: map[string]NetworkV2Ethernet{"eth0": {}},
+				VLANs:     map[string]NetworkV2VLAN{"vlan0": {Link: "eth0"}},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidVLANID},
+			},
+		},
+		{
+			name: "negative case: invalid route",
+			config: NetworkConfigV2{
+				Ethernets: map[string]NetworkV2Ethernet{
+					"eth0": {NetworkV2Device: NetworkV2Device{Routes: []NetworkV2Route{{To: "default", Via: "router"}}}},
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidIPAddress},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.config.Render(buf)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, buf.String())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+)
+
+const (
+	ovfEnvFileName = "ovf-env.xml"
+	ovfEnvVolumeID = "OVF ENV"
+	ovfEnvFileMode = 0o644
+)
+
+type OVFEnvironment struct {
+	ID         string
+	UserData   Multipart
+	InstanceID string
+	Hostname   string
+	PublicKeys string
+	SeedFrom   string
+}
+
+func (e *OVFEnvironment) Render(w io.Writer) error {
+	var userData string
+	if e.UserData != nil {
+		b, err := renderBytes(e.UserData)
+		if err != nil {
+			logger.Println("failed to render ovf environment", "func", getFuncName(), "environment", e, "error", err)
+			return err
+		}
+
+		userData = base64.StdEncoding.EncodeToString(b)
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<Environment xmlns="http://schemas.dmtf.org/ovf/environment/1"` + "\n")
+	buf.WriteString(`    xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"` + "\n")
+	buf.WriteString(`    xmlns:oe="http://schemas.dmtf.org/ovf/environment/1"`)
+	if e.ID != "" {
+		buf.WriteString("\n    oe:id=\"")
+		_ = xml.EscapeText(buf, []byte(e.ID))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString(">\n")
+	buf.WriteString("  <PropertySection>\n")
+
+	for _, prop := range []struct {
+		key   string
+		value string
+	}{
+		{key: "instance-id", value: e.InstanceID},
+		{key: "hostname", value: e.Hostname},
+		{key: "seedfrom", value: e.SeedFrom},
+		{key: "public-keys", value: e.PublicKeys},
+		{key: "user-data", value: userData},
+	} {
+		if prop.value == "" {
+			continue
+		}
+
+		buf.WriteString(`    <Property oe:key="` + prop.key + `" oe:value="`)
+		_ = xml.EscapeText(buf, []byte(prop.value))
+		buf.WriteString("\"/>\n")
+	}
+
+	buf.WriteString("  </PropertySection>\n")
+	buf.WriteString("</Environment>\n")
+
+	if _, err := buf.WriteTo(w); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render ovf environment", "func", getFuncName(), "environment", e, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (e *OVFEnvironment) WriteISO9660(w io.Writer) error {
+	img, err := NewISO9660Image(ovfEnvVolumeID)
+	if err != nil {
+		logger.Println("failed to write ovf environment", "func", getFuncName(), "environment", e, "error", err)
+		return err
+	}
+
+	b, err := renderBytes(e)
+	if err != nil {
+		logger.Println("failed to write ovf environment", "func", getFuncName(), "environment", e, "error", err)
+		return err
+	}
+
+	if err := img.WriteFile(ovfEnvFileName, b, ovfEnvFileMode); err != nil {
+		logger.Println("failed to write ovf environment", "func", getFuncName(), "environment", e, "error", err)
+		return err
+	}
+
+	if err := img.Render(w); err != nil {
+		logger.Println("failed to write ovf environment", "func", getFuncName(), "environment", e, "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOVFEnvironment_Render(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name        string
+		environment OVFEnvironment
+		expected    expected
+	}{
+		{
+			name: "positive case",
+			environment: OVFEnvironment{
+				ID: "WebTier",
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					return m
+				}(),
+				InstanceID: "iid-ovf01",
+				Hostname:   "web01",
+				PublicKeys: "ssh-ed25519 AAAA admin",
+				SeedFrom:   "http://192.0.2.1/seed/",
+			},
+			expected: expected{
+				res: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+					"<Environment xmlns=\"http://schemas.dmtf.org/ovf/environment/1\"\n" +
+					"    xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\"\n" +
+					"    xmlns:oe=\"http://schemas.dmtf.org/ovf/environment/1\"\n" +
+					"    oe:id=\"WebTier\">\n" +
+					"  <PropertySection>\n" +
+					"    <Property oe:key=\"instance-id\" oe:value=\"iid-ovf01\"/>\n" +
+					"    <Property oe:key=\"hostname\" oe:value=\"web01\"/>\n" +
+					"    <Property oe:key=\"seedfrom\" oe:value=\"http://192.0.2.1/seed/\"/>\n" +
+					"    <Property oe:key=\"public-keys\" oe:value=\"ssh-ed25519 AAAA admin\"/>\n" +
+					// base64 of the rendered empty multipart
+					"    <Property oe:key=\"user-data\" oe:value=\"Q29udGVudC1UeXBlOiBtdWx0aXBhcnQvbWl4ZWQ7IGJvdW5kYXJ5PSIrR28rVXNlcitEYXRhK0JvdW5kYXJ5PT0iDQpNaW1lLVZlcnNpb246IDEuMA0KDQotLStHbytVc2VyK0RhdGErQm91bmRhcnk9PS0tDQo=\"/>\n" +
+					"  </PropertySection>\n" +
+					"</Environment>\n",
+			},
+		},
+		{
+			name: "positive case: escaped",
+			environment: OVFEnvironment{
+				InstanceID: "iid-ovf01",
+				PublicKeys: "ssh-ed25519 AAAA \"admin\"\n" + "ssh-ed25519 BBBB <deploy>",
+			},
+			expected: expected{
+				res: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n" +
+					"<Environment xmlns=\"http://schemas.dmtf.org/ovf/environment/1\"\n" +
+					"    xmlns:xsi=\"http://www.w3.org/2001/XMLSchema-instance\"\n" +
+					"    xmlns:oe=\"http://schemas.dmtf.org/ovf/environment/1\">\n" +
+					"  <PropertySection>\n" +
+					"    <Property oe:key=\"instance-id\" oe:value=\"iid-ovf01\"/>\n" +
+					"    <Property oe:key=\"public-keys\" oe:value=\"ssh-ed25519 AAAA &#34;admin&#34;&#xA;ssh-ed25519 BBBB &lt;deploy&gt;\"/>\n" +
+					"  </PropertySection>\n" +
+					"</Environment>\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.environment.Render(buf)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, buf.String())
+
+				var doc struct {
+					Properties []struct {
+						Key   string `xml:"key,attr"`
+						Value string `xml:"value,attr"`
+					} `xml:"PropertySection>Property"`
+				}
+				assert.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+				for _, prop := range doc.Properties {
+					if prop.Key == "public-keys" {
+						assert.Equal(t, tt.environment.PublicKeys, prop.Value)
+					}
+				}
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestOVFEnvironment_WriteISO9660(t *testing.T) {
+	e := OVFEnvironment{InstanceID: "iid-ovf01"}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, e.WriteISO9660(buf))
+
+	content := readISO9660(buf.Bytes(), false)
+	assert.Equal(t, "OVF ENV", content.volumeID)
+	assert.Contains(t, string(content.files["ovf-env.xml"]), "<Property oe:key=\"instance-id\" oe:value=\"iid-ovf01\"/>")
+}
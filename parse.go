@@ -0,0 +1,183 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	mimemultipart "mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+func Parse(r io.Reader) (Multipart, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		err = &Error{Op: "parse", Err: err}
+		logger.Println("failed to parse user data", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	if bytes.HasPrefix(data, gzipMagic) {
+		b, err := gunzip(data)
+		if err != nil {
+			logger.Println("failed to parse user data", "func", getFuncName(), "error", err)
+			return nil, err
+		}
+
+		data = b
+	}
+
+	m, err := parseMultipart(data)
+	if err != nil {
+		logger.Println("failed to parse user data", "func", getFuncName(), "error", err)
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func parseMultipart(data []byte) (Multipart, error) {
+	tr := textproto.NewReader(bufio.NewReader(bytes.NewReader(data)))
+	h, err := tr.ReadMIMEHeader()
+	if err != nil || h.Get("Content-Type") == "" {
+		// a bare document is wrapped as the only part
+		mediaType, err := DetectMediaType(data)
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := NewPart(mediaType, data)
+		if err != nil {
+			return nil, err
+		}
+
+		return singlePart(p)
+	}
+
+	typ, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return nil, &Error{Op: "parse", Err: ErrInvalidMediaType}
+	}
+
+	if !strings.HasPrefix(typ, "multipart/") {
+		body, err := io.ReadAll(tr.R)
+		if err != nil {
+			return nil, &Error{Op: "parse", Err: err}
+		}
+
+		p, err := parsePart(h, body)
+		if err != nil {
+			return nil, err
+		}
+
+		return singlePart(p)
+	}
+
+	m, err := NewMultipartWithBoundary(params["boundary"])
+	if err != nil {
+		return nil, err
+	}
+
+	mr := mimemultipart.NewReader(tr.R, params["boundary"])
+	for {
+		mp, err := mr.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, &Error{Op: "parse", Err: err}
+		}
+
+		body, err := io.ReadAll(mp)
+		if err != nil {
+			return nil, &Error{Op: "parse", Err: err}
+		}
+
+		// Render terminates every body with a CRLF of its own
+		p, err := parsePart(mp.Header, bytes.TrimSuffix(body, []byte("\r\n")))
+		if err != nil {
+			return nil, err
+		}
+		m.Append(p)
+	}
+
+	return m, nil
+}
+
+func parsePart(h textproto.MIMEHeader, body []byte) (Part, error) {
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return nil, &Error{Op: "parse", Err: ErrInvalidMediaType}
+	}
+
+	if strings.EqualFold(h.Get("Content-Transfer-Encoding"), string(EncodingBase64)) {
+		b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(body)), ""))
+		if err != nil {
+			return nil, &Error{Op: "parse", Err: ErrInvalidBody}
+		}
+
+		body = b
+	}
+
+	if _, params, err := mime.ParseMediaType(h.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		return NewPartWithFilename(MediaType(mediaType), body, params["filename"])
+	}
+
+	return NewPart(MediaType(mediaType), body)
+}
+
+func singlePart(p Part) (Multipart, error) {
+	m, err := NewMultipart()
+	if err != nil {
+		return nil, err
+	}
+	m.Append(p)
+
+	return m, nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, &Error{Op: "parse", Err: err}
+	}
+
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, &Error{Op: "parse", Err: err}
+	}
+
+	if err := zr.Close(); err != nil {
+		return nil, &Error{Op: "parse", Err: err}
+	}
+
+	return b, nil
+}
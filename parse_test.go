@@ -0,0 +1,187 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	type expected struct {
+		res []Part
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: multipart",
+			args: args{
+				data: []byte("Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+					"Mime-Version: 1.0\r\n" +
+					"\r\n" +
+					"--+Go+User+Data+Boundary==\r\n" +
+					"Content-Transfer-Encoding: 7bit\r\n" +
+					"Content-Type: text/cloud-config; charset=us-ascii\r\n" +
+					"\r\n" +
+					"#cloud-config\n" +
+					"timezone: Asia/Tokyo\r\n" +
+					"\r\n" +
+					"--+Go+User+Data+Boundary==\r\n" +
+					"Content-Transfer-Encoding: base64\r\n" +
+					"Content-Type: text/x-shellscript; charset=utf-8\r\n" +
+					"\r\n" +
+					"IyEvYmluL2Jhc2gKZWNobyAn44GT44KT44Gr44Gh44Gv5LiW55WMJw==\r\n" +
+					"\r\n" +
+					"--+Go+User+Data+Boundary==--\r\n"),
+			},
+			expected: expected{
+				res: []Part{
+					mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Asia/Tokyo")),
+					mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'こんにちは世界'")),
+				},
+			},
+		},
+		{
+			name: "positive case: single part",
+			args: args{
+				data: []byte("Content-Type: text/x-shellscript\r\n" +
+					"\r\n" +
+					"#!/bin/sh\n" +
+					"echo hello\n"),
+			},
+			expected: expected{
+				res: []Part{
+					mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\n"+"echo hello\n")),
+				},
+			},
+		},
+		{
+			name: "positive case: bare document",
+			args: args{
+				data: []byte("#cloud-config\n" + "timezone: Europe/London\n"),
+			},
+			expected: expected{
+				res: []Part{
+					mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London\n")),
+				},
+			},
+		},
+		{
+			name: "positive case: gzip",
+			args: args{
+				data: func() []byte {
+					b, _ := gzipBytes([]byte("#!/bin/sh\n" + "echo hello\n"))
+					return b
+				}(),
+			},
+			expected: expected{
+				res: []Part{
+					mustNewPart(MediaTypeXShellscript, []byte("#!/bin/sh\n"+"echo hello\n")),
+				},
+			},
+		},
+		{
+			name: "negative case: unknown document",
+			args: args{
+				data: []byte("hello"),
+			},
+			expected: expected{
+				err: &Error{Op: "detect", Err: ErrUnknownMediaType},
+			},
+		},
+		{
+			name: "negative case: unknown part media type",
+			args: args{
+				data: []byte("Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+					"\r\n" +
+					"--+Go+User+Data+Boundary==\r\n" +
+					"Content-Type: text/x-unknown\r\n" +
+					"\r\n" +
+					"hello\r\n" +
+					"--+Go+User+Data+Boundary==--\r\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrUnknownMediaType},
+			},
+		},
+		{
+			name: "negative case: invalid base64",
+			args: args{
+				data: []byte("Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+					"\r\n" +
+					"--+Go+User+Data+Boundary==\r\n" +
+					"Content-Transfer-Encoding: base64\r\n" +
+					"Content-Type: text/x-shellscript\r\n" +
+					"\r\n" +
+					"!!!\r\n" +
+					"--+Go+User+Data+Boundary==--\r\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "parse", Err: ErrInvalidBody},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Parse(bytes.NewReader(tt.args.data))
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual.Parts())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestParse_roundTrip(t *testing.T) {
+	m, _ := NewMultipartWithBoundary("+Custom+User+Data+Boundary+")
+	m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London\n")))
+	m.Append(mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/bash\n"+"echo 'こんにちは世界'\n")))
+	p, _ := NewPartWithFilename(MediaTypeXShellscript, []byte("#ps1_sysnative\r\n"+"Write-Host 'Hello World'\r\n"), "setup.ps1")
+	m.Append(p)
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, m.Render(buf))
+	expected := buf.String()
+
+	actual, err := Parse(strings.NewReader(expected))
+	assert.NoError(t, err)
+
+	buf.Reset()
+	assert.NoError(t, actual.Render(buf))
+	assert.Equal(t, expected, buf.String())
+}
@@ -25,35 +25,108 @@ import (
 	"fmt"
 	"io"
 	"mime"
+	"strings"
 
+	"golang.org/x/exp/slices"
 	"golang.org/x/exp/utf8string"
 )
 
 type Part interface {
+	MediaType() MediaType
+	Body() []byte
+	Filename() string
 	Renderer
 }
 
 type part struct {
-	header Header
-	body   []byte
+	header    Header
+	body      []byte
+	mediaType MediaType
 }
 
-func NewPart(mediaType MediaType, body []byte) Part {
+func NewPart(mediaType MediaType, body []byte) (Part, error) {
+	spec, ok := lookupMediaType(mediaType)
+	if !ok {
+		err := &Error{Op: "initialize", Err: ErrUnknownMediaType}
+		logger.Println("failed to initialize part", "func", getFuncName(), "mediaType", mediaType, "error", err)
+		return nil, err
+	}
+
+	if spec.Validator != nil {
+		if err := spec.Validator(body); err != nil {
+			err = &Error{Op: "validate", Err: err}
+			logger.Println("failed to initialize part", "func", getFuncName(), "mediaType", mediaType, "error", err)
+			return nil, err
+		}
+	}
+
 	charset := "us-ascii"
-	enc := "7bit"
+	enc := Encoding7bit
 	if !utf8string.NewString(string(body)).IsASCII() {
 		charset = "utf-8"
-		enc = "base64"
+		enc = EncodingBase64
+	}
+
+	if spec.Encoding == EncodingBase64 {
+		enc = EncodingBase64
+	}
+
+	if enc == EncodingBase64 {
 		body = []byte(base64.StdEncoding.EncodeToString(body))
 	}
 
 	typ := mime.FormatMediaType(string(mediaType), map[string]string{"charset": charset})
 
 	h := NewHeader()
-	h.Set("Content-Transfer-Encoding", enc)
+	h.Set("Content-Transfer-Encoding", string(enc))
 	h.Set("Content-Type", typ)
 
-	return &part{header: h, body: body}
+	return &part{header: h, body: body, mediaType: mediaType}, nil
+}
+
+func NewPartWithFilename(mediaType MediaType, body []byte, filename string) (Part, error) {
+	if filename == "" || strings.ContainsAny(filename, `/\`) {
+		err := &Error{Op: "initialize", Err: ErrInvalidName}
+		logger.Println("failed to initialize part", "func", getFuncName(), "mediaType", mediaType, "filename", filename, "error", err)
+		return nil, err
+	}
+
+	p, err := NewPart(mediaType, body)
+	if err != nil {
+		logger.Println("failed to initialize part", "func", getFuncName(), "mediaType", mediaType, "filename", filename, "error", err)
+		return nil, err
+	}
+
+	p.(*part).header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	return p, nil
+}
+
+func (p *part) MediaType() MediaType {
+	return p.mediaType
+}
+
+func (p *part) Body() []byte {
+	if p.header.Get("Content-Transfer-Encoding") != string(EncodingBase64) {
+		return slices.Clone(p.body)
+	}
+
+	body, err := base64.StdEncoding.DecodeString(string(p.body))
+	if err != nil {
+		logger.Println("failed to decode part body", "func", getFuncName(), "part", p, "error", err)
+		return slices.Clone(p.body)
+	}
+
+	return body
+}
+
+func (p *part) Filename() string {
+	_, params, err := mime.ParseMediaType(p.header.Get("Content-Disposition"))
+	if err != nil {
+		return ""
+	}
+
+	return params["filename"]
 }
 
 func (p *part) Render(w io.Writer) error {
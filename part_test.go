@@ -22,6 +22,7 @@ package userdata
 
 import (
 	"bytes"
+	"errors"
 	"net/textproto"
 	"testing"
 
@@ -29,6 +30,8 @@ import (
 )
 
 func TestNewPart(t *testing.T) {
+	errAcmeDisabled := errors.New("acme disabled")
+
 	type args struct {
 		mediaType MediaType
 		body      []byte
@@ -36,6 +39,7 @@ func TestNewPart(t *testing.T) {
 
 	type expected struct {
 		res Part
+		err error
 	}
 
 	tests := []struct {
@@ -57,7 +61,8 @@ func TestNewPart(t *testing.T) {
 							"Content-Type":              {"text/x-shellscript; charset=us-ascii"},
 						},
 					},
-					body: []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+					body:      []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+					mediaType: MediaTypeXShellscript,
 				},
 			},
 		},
@@ -79,15 +84,167 @@ func TestNewPart(t *testing.T) {
 						// base64.StdEncoding.EncodeToString([]byte("#!/bin/bash\n" + "echo 'こんにちは世界'")),
 						"IyEvYmluL2Jhc2gKZWNobyAn44GT44KT44Gr44Gh44Gv5LiW55WMJw==",
 					),
+					mediaType: MediaTypeXShellscript,
+				},
+			},
+		},
+		{
+			name: "positive case: registered base64",
+			args: args{
+				mediaType: "application/x-acme-config",
+				body:      []byte("acme: true"),
+			},
+			expected: expected{
+				res: &part{
+					header: &header{
+						textproto.MIMEHeader{
+							"Content-Transfer-Encoding": {"base64"},
+							"Content-Type":              {"application/x-acme-config; charset=us-ascii"},
+						},
+					},
+					body: []byte(
+						// base64.StdEncoding.EncodeToString([]byte("acme: true")),
+						"YWNtZTogdHJ1ZQ==",
+					),
+					mediaType: "application/x-acme-config",
 				},
 			},
 		},
+		{
+			name: "negative case: unknown media type",
+			args: args{
+				mediaType: "text/cloud-conifg",
+				body:      []byte("#cloud-config\n" + "timezone: Europe/London"),
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrUnknownMediaType},
+			},
+		},
+		{
+			name: "negative case: rejected by validator",
+			args: args{
+				mediaType: "application/x-acme-config",
+				body:      []byte("acme: false"),
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: errAcmeDisabled},
+			},
+		},
 	}
 
+	assert.NoError(t, RegisterMediaType(MediaTypeSpec{
+		MediaType: "application/x-acme-config",
+		Encoding:  EncodingBase64,
+		Validator: func(body []byte) error {
+			if !bytes.Equal(body, []byte("acme: true")) {
+				return errAcmeDisabled
+			}
+
+			return nil
+		},
+	}))
+	t.Cleanup(func() { unregisterMediaType("application/x-acme-config") })
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := NewPart(tt.args.mediaType, tt.args.body)
-			assert.Equal(t, tt.expected.res, actual)
+			actual, err := NewPart(tt.args.mediaType, tt.args.body)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestNewPartWithFilename(t *testing.T) {
+	type args struct {
+		mediaType MediaType
+		body      []byte
+		filename  string
+	}
+
+	type expected struct {
+		res Part
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case",
+			args: args{
+				mediaType: MediaTypeXShellscript,
+				body:      []byte("#ps1_sysnative\r\n" + "Write-Host 'Hello World'"),
+				filename:  "hello world.ps1",
+			},
+			expected: expected{
+				res: &part{
+					header: &header{
+						textproto.MIMEHeader{
+							"Content-Disposition":       {"attachment; filename=\"hello world.ps1\""},
+							"Content-Transfer-Encoding": {"7bit"},
+							"Content-Type":              {"text/x-shellscript; charset=us-ascii"},
+						},
+					},
+					body:      []byte("#ps1_sysnative\r\n" + "Write-Host 'Hello World'"),
+					mediaType: MediaTypeXShellscript,
+				},
+			},
+		},
+		{
+			name: "negative case: empty filename",
+			args: args{
+				mediaType: MediaTypeXShellscript,
+				body:      []byte("#!/bin/bash\n"),
+				filename:  "",
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrInvalidName},
+			},
+		},
+		{
+			name: "negative case: path separator",
+			args: args{
+				mediaType: MediaTypeXShellscript,
+				body:      []byte("#!/bin/bash\n"),
+				filename:  `..\setup.ps1`,
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrInvalidName},
+			},
+		},
+		{
+			name: "negative case: unknown media type",
+			args: args{
+				mediaType: "text/cloud-conifg",
+				body:      []byte("#cloud-config\n"),
+				filename:  "config.yaml",
+			},
+			expected: expected{
+				err: &Error{Op: "initialize", Err: ErrUnknownMediaType},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewPartWithFilename(tt.args.mediaType, tt.args.body, tt.args.filename)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+				assert.Equal(t, tt.args.filename, actual.Filename())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
 		})
 	}
 }
@@ -105,7 +262,7 @@ func TestPart_Render(t *testing.T) {
 	}{
 		{
 			name: "positive case: ascii",
-			part: NewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'Hello World'")),
+			part: mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'Hello World'")),
 			expected: expected{
 				res: "Content-Transfer-Encoding: 7bit\r\n" +
 					"Content-Type: text/x-shellscript; charset=us-ascii\r\n" +
@@ -117,7 +274,7 @@ func TestPart_Render(t *testing.T) {
 		},
 		{
 			name: "positive case: utf-8",
-			part: NewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'こんにちは世界'")),
+			part: mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'こんにちは世界'")),
 			expected: expected{
 				res: "Content-Transfer-Encoding: base64\r\n" +
 					"Content-Type: text/x-shellscript; charset=utf-8\r\n" +
@@ -144,3 +301,58 @@ func TestPart_Render(t *testing.T) {
 		})
 	}
 }
+
+func TestPart_Body(t *testing.T) {
+	type expected struct {
+		res []byte
+	}
+
+	tests := []struct {
+		name     string
+		part     Part
+		expected expected
+	}{
+		{
+			name: "positive case: ascii",
+			part: mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'Hello World'")),
+			expected: expected{
+				res: []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+			},
+		},
+		{
+			name: "positive case: utf-8",
+			part: mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\n"+"echo 'こんにちは世界'")),
+			expected: expected{
+				res: []byte("#!/bin/bash\n" + "echo 'こんにちは世界'"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected.res, tt.part.Body())
+		})
+	}
+}
+
+func mustNewPart(mediaType MediaType, body []byte) Part {
+	p, err := NewPart(mediaType, body)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+func mustNewMultipart(parts ...Part) Multipart {
+	m, err := NewMultipart()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, p := range parts {
+		m.Append(p)
+	}
+
+	return m
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	defaultHandlerVersion = 2
+)
+
+type PartHandler struct {
+	MediaTypes []MediaType
+	Version    int
+	Frequency  Frequency
+	Body       []byte
+}
+
+func (h *PartHandler) Build() (Part, error) {
+	if err := h.validate(); err != nil {
+		logger.Println("failed to build part handler", "func", getFuncName(), "handler", h, "error", err)
+		return nil, err
+	}
+
+	version := h.Version
+	if version == 0 {
+		version = defaultHandlerVersion
+	}
+
+	frequency := h.Frequency
+	if frequency == "" {
+		frequency = FrequencyPerInstance
+	}
+
+	args := []string{"data", "ctype", "filename", "payload"}
+	if version >= 2 {
+		args = append(args, "frequency")
+	}
+	if version >= 3 {
+		args = append(args, "headers")
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprint(buf, "#part-handler\n")
+	fmt.Fprintf(buf, "handler_version = %d\n", version)
+	fmt.Fprintf(buf, "frequency = \"%s\"\n", frequency)
+	fmt.Fprint(buf, "\n\n")
+	fmt.Fprint(buf, "def list_types():\n")
+	fmt.Fprint(buf, "    return [\n")
+	for _, mt := range h.MediaTypes {
+		fmt.Fprintf(buf, "        \"%s\",\n", mt)
+	}
+	fmt.Fprint(buf, "    ]\n")
+	fmt.Fprint(buf, "\n\n")
+	fmt.Fprintf(buf, "def handle_part(%s):\n", strings.Join(args, ", "))
+	fmt.Fprint(buf, "    if ctype in (\"__begin__\", \"__end__\"):\n")
+	fmt.Fprint(buf, "        return\n")
+	fmt.Fprint(buf, "\n")
+	fmt.Fprint(buf, indentPython(h.Body))
+
+	return NewPart(MediaTypePartHandler, buf.Bytes())
+}
+
+func (h *PartHandler) validate() error {
+	if len(h.MediaTypes) == 0 {
+		return &Error{Op: "build", Err: ErrInvalidMediaType}
+	}
+
+	seen := make([]MediaType, 0, len(h.MediaTypes))
+	for _, mt := range h.MediaTypes {
+		typ, params, err := mime.ParseMediaType(string(mt))
+		if err != nil || len(params) > 0 || typ != string(mt) {
+			return &Error{Op: "build", Err: ErrInvalidMediaType}
+		}
+
+		if slices.Contains(builtinMediaTypes, mt) {
+			return &Error{Op: "build", Err: ErrReservedMediaType}
+		}
+
+		if slices.Contains(seen, mt) {
+			return &Error{Op: "build", Err: ErrDuplicateMediaType}
+		}
+
+		seen = append(seen, mt)
+	}
+
+	if h.Version < 0 || h.Version > 3 {
+		return &Error{Op: "build", Err: ErrInvalidHandlerVersion}
+	}
+
+	switch h.Frequency {
+	case "", FrequencyAlways, FrequencyPerInstance:
+	default:
+		return &Error{Op: "build", Err: ErrInvalidFrequency}
+	}
+
+	return nil
+}
+
+func indentPython(body []byte) string {
+	src := strings.ReplaceAll(string(body), "\r\n", "\n")
+	src = strings.TrimRight(src, " \t\n")
+	if strings.TrimSpace(src) == "" {
+		return "    pass\n"
+	}
+
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+			continue
+		}
+
+		lines[i] = "    " + line
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
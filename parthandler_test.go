@@ -0,0 +1,198 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartHandler_Build(t *testing.T) {
+	type expected struct {
+		res Part
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		handler  PartHandler
+		expected expected
+	}{
+		{
+			name: "positive case: defaults",
+			handler: PartHandler{
+				MediaTypes: []MediaType{"text/x-acme-bootstrap"},
+				Body:       []byte("print(payload)"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypePartHandler, []byte("#part-handler\n"+
+					"handler_version = 2\n"+
+					"frequency = \"once-per-instance\"\n"+
+					"\n"+
+					"\n"+
+					"def list_types():\n"+
+					"    return [\n"+
+					"        \"text/x-acme-bootstrap\",\n"+
+					"    ]\n"+
+					"\n"+
+					"\n"+
+					"def handle_part(data, ctype, filename, payload, frequency):\n"+
+					"    if ctype in (\"__begin__\", \"__end__\"):\n"+
+					"        return\n"+
+					"\n"+
+					"    print(payload)\n",
+				)),
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: version 3 always",
+			handler: PartHandler{
+				MediaTypes: []MediaType{"text/x-acme-bootstrap", "application/x-acme-config"},
+				Version:    3,
+				Frequency:  FrequencyAlways,
+				Body:       []byte("if ctype == \"text/x-acme-bootstrap\":\n    print(payload)\n\nprint(headers)\n"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypePartHandler, []byte("#part-handler\n"+
+					"handler_version = 3\n"+
+					"frequency = \"always\"\n"+
+					"\n"+
+					"\n"+
+					"def list_types():\n"+
+					"    return [\n"+
+					"        \"text/x-acme-bootstrap\",\n"+
+					"        \"application/x-acme-config\",\n"+
+					"    ]\n"+
+					"\n"+
+					"\n"+
+					"def handle_part(data, ctype, filename, payload, frequency, headers):\n"+
+					"    if ctype in (\"__begin__\", \"__end__\"):\n"+
+					"        return\n"+
+					"\n"+
+					"    if ctype == \"text/x-acme-bootstrap\":\n"+
+					"        print(payload)\n"+
+					"\n"+
+					"    print(headers)\n",
+				)),
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: version 1 empty body",
+			handler: PartHandler{
+				MediaTypes: []MediaType{"text/x-acme-bootstrap"},
+				Version:    1,
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypePartHandler, []byte("#part-handler\n"+
+					"handler_version = 1\n"+
+					"frequency = \"once-per-instance\"\n"+
+					"\n"+
+					"\n"+
+					"def list_types():\n"+
+					"    return [\n"+
+					"        \"text/x-acme-bootstrap\",\n"+
+					"    ]\n"+
+					"\n"+
+					"\n"+
+					"def handle_part(data, ctype, filename, payload):\n"+
+					"    if ctype in (\"__begin__\", \"__end__\"):\n"+
+					"        return\n"+
+					"\n"+
+					"    pass\n",
+				)),
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: no media types",
+			handler: PartHandler{
+				Body: []byte("print(payload)"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidMediaType},
+			},
+		},
+		{
+			name: "negative case: invalid media type",
+			handler: PartHandler{
+				MediaTypes: []MediaType{"text/x-acme-bootstrap; charset=us-ascii"},
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidMediaType},
+			},
+		},
+		{
+			name: "negative case: reserved media type",
+			handler: PartHandler{
+				MediaTypes: []MediaType{MediaTypeCloudConfig},
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrReservedMediaType},
+			},
+		},
+		{
+			name: "negative case: duplicate media type",
+			handler: PartHandler{
+				MediaTypes: []MediaType{"text/x-acme-bootstrap", "text/x-acme-bootstrap"},
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrDuplicateMediaType},
+			},
+		},
+		{
+			name: "negative case: invalid version",
+			handler: PartHandler{
+				MediaTypes: []MediaType{"text/x-acme-bootstrap"},
+				Version:    4,
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidHandlerVersion},
+			},
+		},
+		{
+			name: "negative case: invalid frequency",
+			handler: PartHandler{
+				MediaTypes: []MediaType{"text/x-acme-bootstrap"},
+				Frequency:  "once",
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidFrequency},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.handler.Build()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
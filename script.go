@@ -0,0 +1,182 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+var (
+	envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	posixShells = []string{"sh", "ash", "bash", "dash", "ksh", "zsh"}
+)
+
+type Script struct {
+	Frequency   Frequency
+	Interpreter string
+	Env         map[string]string
+	Body        []byte
+}
+
+func (s *Script) Build() (Part, error) {
+	mediaType, err := s.mediaType()
+	if err != nil {
+		logger.Println("failed to build script", "func", getFuncName(), "script", s, "error", err)
+		return nil, err
+	}
+
+	shebang, body, err := s.split()
+	if err != nil {
+		logger.Println("failed to build script", "func", getFuncName(), "script", s, "error", err)
+		return nil, err
+	}
+
+	env, err := s.exports(shebang)
+	if err != nil {
+		logger.Println("failed to build script", "func", getFuncName(), "script", s, "error", err)
+		return nil, err
+	}
+
+	for _, warn := range s.Lint() {
+		logger.Println("script has warning", "func", getFuncName(), "script", s, "warning", warn)
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(shebang)
+	buf.WriteString("\n")
+	buf.WriteString(env)
+	buf.Write(body)
+
+	return NewPart(mediaType, buf.Bytes())
+}
+
+func (s *Script) Lint() []error {
+	warns := make([]error, 0)
+
+	if bytes.Contains(s.Body, []byte("\r\n")) {
+		warns = append(warns, &Error{Op: "lint", Err: ErrCRLFLineEndings})
+	}
+
+	return warns
+}
+
+func (s *Script) mediaType() (MediaType, error) {
+	switch s.Frequency {
+	// plain text/x-shellscript runs once per instance, so only the per-boot type reruns
+	case FrequencyAlways, FrequencyPerBoot:
+		return MediaTypeXShellscriptPerBoot, nil
+	case FrequencyPerInstance:
+		return MediaTypeXShellscriptPerInstance, nil
+	case FrequencyOnce:
+		return MediaTypeXShellscriptPerOnce, nil
+	default:
+		return "", &Error{Op: "build", Err: ErrInvalidFrequency}
+	}
+}
+
+func (s *Script) split() (string, []byte, error) {
+	var shebang string
+	body := s.Body
+	if bytes.HasPrefix(body, []byte("#!")) {
+		line, rest, _ := bytes.Cut(body, []byte("\n"))
+		shebang = strings.TrimRight(string(line), "\r")
+		body = rest
+	}
+
+	if s.Interpreter == "" {
+		if shebang == "" {
+			return "", nil, &Error{Op: "build", Err: ErrMissingShebang}
+		}
+
+		return shebang, body, nil
+	}
+
+	want := "#!" + s.Interpreter
+	if shebang != "" && shebang != want {
+		return "", nil, &Error{Op: "build", Err: ErrInvalidShebang}
+	}
+
+	return want, body, nil
+}
+
+func (s *Script) exports(shebang string) (string, error) {
+	if len(s.Env) == 0 {
+		return "", nil
+	}
+
+	if !isPOSIXShell(shebang) {
+		return "", &Error{Op: "build", Err: ErrUnsupportedEnv}
+	}
+
+	keys := maps.Keys(s.Env)
+	slices.Sort(keys)
+
+	buf := new(strings.Builder)
+	for _, k := range keys {
+		if !envNameRe.MatchString(k) {
+			return "", &Error{Op: "build", Err: ErrInvalidEnvName}
+		}
+
+		v := s.Env[k]
+		if strings.ContainsRune(v, 0) {
+			return "", &Error{Op: "build", Err: ErrInvalidEnvValue}
+		}
+
+		fmt.Fprintf(buf, "export %s=%s\n", k, quoteShell(v))
+	}
+
+	return buf.String(), nil
+}
+
+func isPOSIXShell(shebang string) bool {
+	fields := strings.Fields(strings.TrimPrefix(shebang, "#!"))
+	if len(fields) == 0 {
+		return false
+	}
+
+	name := path.Base(fields[0])
+	if name == "env" {
+		args := fields[1:]
+		for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+			args = args[1:]
+		}
+
+		if len(args) == 0 {
+			return false
+		}
+
+		name = path.Base(args[0])
+	}
+
+	return slices.Contains(posixShells, name)
+}
+
+func quoteShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
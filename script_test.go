@@ -0,0 +1,224 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScript_Build(t *testing.T) {
+	type expected struct {
+		res Part
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		script   Script
+		expected expected
+	}{
+		{
+			name: "positive case: always",
+			script: Script{
+				Frequency: FrequencyAlways,
+				Body:      []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/bash\n"+"echo 'Hello World'")),
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: per-boot",
+			script: Script{
+				Frequency: FrequencyPerBoot,
+				Body:      []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/bash\n"+"echo 'Hello World'")),
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: per-instance",
+			script: Script{
+				Frequency: FrequencyPerInstance,
+				Body:      []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypeXShellscriptPerInstance, []byte("#!/bin/bash\n"+"echo 'Hello World'")),
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: once",
+			script: Script{
+				Frequency: FrequencyOnce,
+				Body:      []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypeXShellscriptPerOnce, []byte("#!/bin/bash\n"+"echo 'Hello World'")),
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: interpreter",
+			script: Script{
+				Frequency:   FrequencyAlways,
+				Interpreter: "/usr/bin/env python3",
+				Body:        []byte("print('Hello World')"),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/usr/bin/env python3\n"+"print('Hello World')")),
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: env",
+			script: Script{
+				Frequency:   FrequencyAlways,
+				Interpreter: "/bin/sh",
+				Env: map[string]string{
+					"NAME":     "it's me",
+					"GREETING": "$HOME `id`",
+				},
+				Body: []byte("#!/bin/sh\n" + "echo \"$GREETING $NAME\""),
+			},
+			expected: expected{
+				res: mustNewPart(MediaTypeXShellscriptPerBoot, []byte("#!/bin/sh\n"+
+					"export GREETING='$HOME `id`'\n"+
+					"export NAME='it'\\''s me'\n"+
+					"echo \"$GREETING $NAME\"",
+				)),
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: missing frequency",
+			script: Script{
+				Body: []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidFrequency},
+			},
+		},
+		{
+			name: "negative case: missing shebang",
+			script: Script{
+				Frequency: FrequencyAlways,
+				Body:      []byte("echo 'Hello World'"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrMissingShebang},
+			},
+		},
+		{
+			name: "negative case: conflicting shebang",
+			script: Script{
+				Frequency:   FrequencyAlways,
+				Interpreter: "/bin/sh",
+				Body:        []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidShebang},
+			},
+		},
+		{
+			name: "negative case: invalid env name",
+			script: Script{
+				Frequency: FrequencyAlways,
+				Env:       map[string]string{"FOO;rm -rf /": "bar"},
+				Body:      []byte("#!/bin/bash\n" + "echo 'Hello World'"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidEnvName},
+			},
+		},
+		{
+			name: "negative case: env with non-shell interpreter",
+			script: Script{
+				Frequency:   FrequencyAlways,
+				Interpreter: "/usr/bin/python3",
+				Env:         map[string]string{"FOO": "bar"},
+				Body:        []byte("print('Hello World')"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrUnsupportedEnv},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.script.Build()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestScript_Lint(t *testing.T) {
+	type expected struct {
+		res []error
+	}
+
+	tests := []struct {
+		name     string
+		script   Script
+		expected expected
+	}{
+		{
+			name: "positive case: lf",
+			script: Script{
+				Frequency: FrequencyAlways,
+				Body:      []byte("#!/bin/bash\n" + "echo 'Hello World'\n"),
+			},
+			expected: expected{
+				res: []error{},
+			},
+		},
+		{
+			name: "positive case: crlf",
+			script: Script{
+				Frequency: FrequencyAlways,
+				Body:      []byte("#!/bin/bash\r\n" + "echo 'Hello World'\r\n"),
+			},
+			expected: expected{
+				res: []error{&Error{Op: "lint", Err: ErrCRLFLineEndings}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.script.Lint()
+			assert.Equal(t, tt.expected.res, actual)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	seedUserData      = "user-data"
+	seedMetaData      = "meta-data"
+	seedVendorData    = "vendor-data"
+	seedNetworkConfig = "network-config"
+
+	seedFileMode = 0o644
+	seedHashSize = 8
+	seedVolumeID = "cidata"
+	seedFATLabel = "CIDATA"
+)
+
+type MetaData struct {
+	InstanceID    string `yaml:"instance-id"`
+	LocalHostname string `yaml:"local-hostname,omitempty"`
+}
+
+func (m *MetaData) Render(w io.Writer) error {
+	if m.InstanceID == "" {
+		err := &Error{Op: "render", Err: ErrMissingInstanceID}
+		logger.Println("failed to render meta-data", "func", getFuncName(), "metaData", m, "error", err)
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	if err := enc.Encode(m); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render meta-data", "func", getFuncName(), "metaData", m, "error", err)
+		return err
+	}
+
+	if err := enc.Close(); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render meta-data", "func", getFuncName(), "metaData", m, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func HashInstanceID(m Multipart, prefix string) (string, error) {
+	var data []byte
+	if m != nil {
+		b, err := renderBytes(m)
+		if err != nil {
+			logger.Println("failed to hash instance id", "func", getFuncName(), "prefix", prefix, "error", err)
+			return "", err
+		}
+
+		data = b
+	}
+
+	sum := sha256.Sum256(data)
+
+	return prefix + hex.EncodeToString(sum[:seedHashSize]), nil
+}
+
+type Seed struct {
+	UserData      Multipart
+	VendorData    Multipart
+	MetaData      MetaData
+	NetworkConfig Renderer
+}
+
+func (s *Seed) DeriveInstanceID(prefix string) error {
+	id, err := HashInstanceID(s.UserData, prefix)
+	if err != nil {
+		logger.Println("failed to derive instance id", "func", getFuncName(), "seed", s, "error", err)
+		return err
+	}
+
+	s.MetaData.InstanceID = id
+
+	return nil
+}
+
+func (s *Seed) Write(sink FileSink) error {
+	files, err := s.files()
+	if err != nil {
+		logger.Println("failed to write seed", "func", getFuncName(), "seed", s, "error", err)
+		return err
+	}
+
+	for _, f := range files {
+		if err := sink.WriteFile(f.name, f.data, seedFileMode); err != nil {
+			logger.Println("failed to write seed", "func", getFuncName(), "seed", s, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Seed) WriteDir(dir string) error {
+	return s.Write(NewDirSink(dir))
+}
+
+func (s *Seed) WriteISO9660(w io.Writer) error {
+	img, err := NewISO9660Image(seedVolumeID)
+	if err != nil {
+		logger.Println("failed to write seed", "func", getFuncName(), "seed", s, "error", err)
+		return err
+	}
+
+	if err := s.Write(img); err != nil {
+		logger.Println("failed to write seed", "func", getFuncName(), "seed", s, "error", err)
+		return err
+	}
+
+	if err := img.Render(w); err != nil {
+		logger.Println("failed to write seed", "func", getFuncName(), "seed", s, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Seed) WriteFAT(w io.Writer) error {
+	img, err := NewFATImage(seedFATLabel)
+	if err != nil {
+		logger.Println("failed to write seed", "func", getFuncName(), "seed", s, "error", err)
+		return err
+	}
+
+	if err := s.Write(img); err != nil {
+		logger.Println("failed to write seed", "func", getFuncName(), "seed", s, "error", err)
+		return err
+	}
+
+	if err := img.Render(w); err != nil {
+		logger.Println("failed to write seed", "func", getFuncName(), "seed", s, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+type seedFile struct {
+	name string
+	data []byte
+}
+
+func (s *Seed) files() ([]seedFile, error) {
+	files := make([]seedFile, 0, 4)
+
+	userData := []byte{}
+	if s.UserData != nil {
+		b, err := renderBytes(s.UserData)
+		if err != nil {
+			return nil, err
+		}
+
+		userData = b
+	}
+	files = append(files, seedFile{name: seedUserData, data: userData})
+
+	metaData, err := renderBytes(&s.MetaData)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, seedFile{name: seedMetaData, data: metaData})
+
+	if s.VendorData != nil {
+		vendorData, err := renderBytes(s.VendorData)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, seedFile{name: seedVendorData, data: vendorData})
+	}
+
+	if s.NetworkConfig != nil {
+		networkConfig, err := renderBytes(s.NetworkConfig)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, seedFile{name: seedNetworkConfig, data: networkConfig})
+	}
+
+	return files, nil
+}
@@ -0,0 +1,340 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapSink map[string][]byte
+
+func (s mapSink) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	s[name] = data
+	return nil
+}
+
+func TestMetaData_Render(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		metaData MetaData
+		expected expected
+	}{
+		{
+			name: "positive case: instance id only",
+			metaData: MetaData{
+				InstanceID: "iid-local01",
+			},
+			expected: expected{
+				res: "instance-id: iid-local01\n",
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: with local hostname",
+			metaData: MetaData{
+				InstanceID:    "iid-local01",
+				LocalHostname: "cloudimg",
+			},
+			expected: expected{
+				res: "instance-id: iid-local01\n" +
+					"local-hostname: cloudimg\n",
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: missing instance id",
+			metaData: MetaData{
+				LocalHostname: "cloudimg",
+			},
+			expected: expected{
+				err: &Error{Op: "render", Err: ErrMissingInstanceID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := tt.metaData.Render(buf)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, buf.String())
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestHashInstanceID(t *testing.T) {
+	type args struct {
+		m      Multipart
+		prefix string
+	}
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: nil",
+			args: args{
+				m: nil,
+			},
+			expected: expected{
+				// sha256 of empty input
+				res: "e3b0c44298fc1c14",
+			},
+		},
+		{
+			name: "positive case: with prefix",
+			args: args{
+				m: func() Multipart {
+					m, _ := NewMultipart()
+					return m
+				}(),
+				prefix: "iid-",
+			},
+			expected: expected{
+				res: "iid-048ad1e54547e5f9",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := HashInstanceID(tt.args.m, tt.args.prefix)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestSeed_DeriveInstanceID(t *testing.T) {
+	newSeed := func(body string) *Seed {
+		m, _ := NewMultipart()
+		m.Append(mustNewPart(MediaTypeCloudConfig, []byte(body)))
+		return &Seed{UserData: m}
+	}
+
+	a, b, c := newSeed("#cloud-config\n"+"timezone: Europe/London"), newSeed("#cloud-config\n"+"timezone: Europe/London"), newSeed("#cloud-config\n"+"timezone: Asia/Tokyo")
+	assert.NoError(t, a.DeriveInstanceID("iid-"))
+	assert.NoError(t, b.DeriveInstanceID("iid-"))
+	assert.NoError(t, c.DeriveInstanceID("iid-"))
+
+	assert.Regexp(t, `^iid-[0-9a-f]{16}$`, a.MetaData.InstanceID)
+	assert.Equal(t, a.MetaData.InstanceID, b.MetaData.InstanceID)
+	assert.NotEqual(t, a.MetaData.InstanceID, c.MetaData.InstanceID)
+}
+
+func TestSeed_Write(t *testing.T) {
+	type expected struct {
+		res mapSink
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		seed     Seed
+		expected expected
+	}{
+		{
+			name: "positive case: user-data and meta-data",
+			seed: Seed{
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					m.Append(mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n"+"timezone: Europe/London")))
+					return m
+				}(),
+				MetaData: MetaData{InstanceID: "iid-local01"},
+			},
+			expected: expected{
+				res: mapSink{
+					"user-data": []byte("Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+						"Mime-Version: 1.0\r\n" +
+						"\r\n" +
+						"--+Go+User+Data+Boundary==\r\n" +
+						"Content-Transfer-Encoding: 7bit\r\n" +
+						"Content-Type: text/cloud-config; charset=us-ascii\r\n" +
+						"\r\n" +
+						"#cloud-config\n" +
+						"timezone: Europe/London\r\n" +
+						"\r\n" +
+						"--+Go+User+Data+Boundary==--\r\n"),
+					"meta-data": []byte("instance-id: iid-local01\n"),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: all files",
+			seed: Seed{
+				UserData: func() Multipart {
+					m, _ := NewMultipart()
+					return m
+				}(),
+				VendorData: func() Multipart {
+					m, _ := NewMultipart()
+					return m
+				}(),
+				MetaData:      MetaData{InstanceID: "iid-local01", LocalHostname: "cloudimg"},
+				NetworkConfig: &MetaData{InstanceID: "network"},
+			},
+			expected: expected{
+				res: mapSink{
+					"user-data": []byte("Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+						"Mime-Version: 1.0\r\n" +
+						"\r\n" +
+						"--+Go+User+Data+Boundary==--\r\n"),
+					"meta-data": []byte("instance-id: iid-local01\n" +
+						"local-hostname: cloudimg\n"),
+					"vendor-data": []byte("Content-Type: multipart/mixed; boundary=\"+Go+User+Data+Boundary==\"\r\n" +
+						"Mime-Version: 1.0\r\n" +
+						"\r\n" +
+						"--+Go+User+Data+Boundary==--\r\n"),
+					"network-config": []byte("instance-id: network\n"),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: network config v2",
+			seed: Seed{
+				MetaData: MetaData{InstanceID: "iid-local01"},
+				NetworkConfig: &NetworkConfigV2{
+					Ethernets: map[string]NetworkV2Ethernet{
+						"eth0": {NetworkV2Device: NetworkV2Device{DHCP4: true}},
+					},
+				},
+			},
+			expected: expected{
+				res: mapSink{
+					"user-data": []byte{},
+					"meta-data": []byte("instance-id: iid-local01\n"),
+					"network-config": []byte("version: 2\n" +
+						"ethernets:\n" +
+						"  eth0:\n" +
+						"    dhcp4: true\n"),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "positive case: empty user-data",
+			seed: Seed{
+				MetaData: MetaData{InstanceID: "iid-local01"},
+			},
+			expected: expected{
+				res: mapSink{
+					"user-data": []byte{},
+					"meta-data": []byte("instance-id: iid-local01\n"),
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "negative case: missing instance id",
+			seed: Seed{},
+			expected: expected{
+				err: &Error{Op: "render", Err: ErrMissingInstanceID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := make(mapSink)
+			err := tt.seed.Write(sink)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, sink)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestSeed_WriteDir(t *testing.T) {
+	dir := t.TempDir()
+
+	seed := Seed{MetaData: MetaData{InstanceID: "iid-local01"}}
+	assert.NoError(t, seed.WriteDir(filepath.Join(dir, "seed")))
+
+	userData, err := os.ReadFile(filepath.Join(dir, "seed", "user-data"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{}, userData)
+
+	metaData, err := os.ReadFile(filepath.Join(dir, "seed", "meta-data"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("instance-id: iid-local01\n"), metaData)
+}
+
+func TestSeed_WriteISO9660(t *testing.T) {
+	seed := Seed{MetaData: MetaData{InstanceID: "iid-local01"}}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, seed.WriteISO9660(buf))
+
+	content := readISO9660(buf.Bytes(), false)
+	assert.Equal(t, "cidata", content.volumeID)
+	assert.Equal(t, []byte("instance-id: iid-local01\n"), content.files["meta-data"])
+	assert.Equal(t, []byte{}, content.files["user-data"])
+}
+
+func TestSeed_WriteFAT(t *testing.T) {
+	seed := Seed{MetaData: MetaData{InstanceID: "iid-local01"}}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, seed.WriteFAT(buf))
+
+	content := readFAT(buf.Bytes())
+	assert.Equal(t, "CIDATA", content.label)
+	assert.Equal(t, []byte("instance-id: iid-local01\n"), content.files["meta-data"])
+	assert.Equal(t, []byte{}, content.files["user-data"])
+}
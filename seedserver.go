@@ -0,0 +1,194 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+type SeedHandler interface {
+	SetMACSeed(mac string, seed *Seed) error
+	SetIPSeed(ip string, seed *Seed) error
+	http.Handler
+}
+
+type seedHandler struct {
+	mu   sync.RWMutex
+	seed *Seed
+	macs map[string]*Seed
+	ips  map[string]*Seed
+}
+
+func NewSeedHandler(seed *Seed) SeedHandler {
+	return &seedHandler{
+		seed: seed,
+		macs: make(map[string]*Seed),
+		ips:  make(map[string]*Seed),
+	}
+}
+
+func (h *seedHandler) SetMACSeed(mac string, seed *Seed) error {
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		err := &Error{Op: "register", Err: ErrInvalidMACAddress}
+		logger.Println("failed to set seed", "func", getFuncName(), "mac", mac, "error", err)
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.macs[hw.String()] = seed
+
+	return nil
+}
+
+func (h *seedHandler) SetIPSeed(ip string, seed *Seed) error {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		err := &Error{Op: "register", Err: ErrInvalidIPAddress}
+		logger.Println("failed to set seed", "func", getFuncName(), "ip", ip, "error", err)
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.ips[addr.String()] = seed
+
+	return nil
+}
+
+func (h *seedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		logger.Println("rejected seed request", "func", getFuncName(), "method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr)
+		return
+	}
+
+	elems := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	name := elems[len(elems)-1]
+
+	var mac string
+	if len(elems) > 1 {
+		if hw, err := net.ParseMAC(elems[len(elems)-2]); err == nil {
+			mac = hw.String()
+		}
+	}
+
+	seed := h.lookup(mac, r.RemoteAddr)
+	if seed == nil {
+		http.NotFound(w, r)
+		logger.Println("no seed for client", "func", getFuncName(), "file", name, "mac", mac, "remote", r.RemoteAddr)
+		return
+	}
+
+	files, err := seed.files()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		logger.Println("failed to serve seed", "func", getFuncName(), "file", name, "mac", mac, "remote", r.RemoteAddr, "error", err)
+		return
+	}
+
+	for _, f := range files {
+		if f.name != name {
+			continue
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", fmt.Sprint(len(f.data)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(f.data)
+		}
+
+		logger.Println("served seed", "func", getFuncName(), "file", name, "mac", mac, "remote", r.RemoteAddr)
+		return
+	}
+
+	http.NotFound(w, r)
+	logger.Println("seed file not found", "func", getFuncName(), "file", name, "mac", mac, "remote", r.RemoteAddr)
+}
+
+func (h *seedHandler) lookup(mac string, remoteAddr string) *Seed {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if seed, ok := h.macs[mac]; ok && mac != "" {
+		return seed
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if seed, ok := h.ips[ip.String()]; ok {
+			return seed
+		}
+	}
+
+	return h.seed
+}
+
+func NoCloudNetKernelCmdline(seedURL string) (string, error) {
+	u, err := noCloudNetURL(seedURL)
+	if err != nil {
+		logger.Println("failed to build kernel command line", "func", getFuncName(), "url", seedURL, "error", err)
+		return "", err
+	}
+
+	return "ds=nocloud-net;s=" + u, nil
+}
+
+func NoCloudNetSMBIOS(seedURL string) (string, error) {
+	u, err := noCloudNetURL(seedURL)
+	if err != nil {
+		logger.Println("failed to build smbios serial", "func", getFuncName(), "url", seedURL, "error", err)
+		return "", err
+	}
+
+	// qemu escapes commas in -smbios values by doubling them
+	return "type=1,serial=ds=nocloud-net;s=" + strings.ReplaceAll(u, ",", ",,"), nil
+}
+
+func noCloudNetURL(seedURL string) (string, error) {
+	u, err := url.Parse(seedURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", &Error{Op: "parse", Err: ErrInvalidURL}
+	}
+
+	if strings.ContainsAny(seedURL, " ;") {
+		return "", &Error{Op: "parse", Err: ErrInvalidURL}
+	}
+
+	if !strings.HasSuffix(seedURL, "/") {
+		seedURL += "/"
+	}
+
+	return seedURL, nil
+}
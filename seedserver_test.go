@@ -0,0 +1,291 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedHandler_ServeHTTP(t *testing.T) {
+	handler := NewSeedHandler(&Seed{MetaData: MetaData{InstanceID: "iid-default"}})
+	_ = handler.SetMACSeed("52:54:00:12:34:56", &Seed{MetaData: MetaData{InstanceID: "iid-mac"}})
+	_ = handler.SetIPSeed("192.0.2.10", &Seed{MetaData: MetaData{InstanceID: "iid-ip"}})
+
+	type args struct {
+		method     string
+		target     string
+		remoteAddr string
+	}
+
+	type expected struct {
+		code int
+		body string
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case: default",
+			args: args{
+				method:     http.MethodGet,
+				target:     "/seed/meta-data",
+				remoteAddr: "192.0.2.1:1234",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "instance-id: iid-default\n",
+			},
+		},
+		{
+			name: "positive case: by mac",
+			args: args{
+				method:     http.MethodGet,
+				target:     "/seed/52-54-00-12-34-56/meta-data",
+				remoteAddr: "192.0.2.10:1234",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "instance-id: iid-mac\n",
+			},
+		},
+		{
+			name: "positive case: by ip",
+			args: args{
+				method:     http.MethodGet,
+				target:     "/meta-data",
+				remoteAddr: "192.0.2.10:1234",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "instance-id: iid-ip\n",
+			},
+		},
+		{
+			name: "positive case: empty user-data",
+			args: args{
+				method:     http.MethodGet,
+				target:     "/user-data",
+				remoteAddr: "192.0.2.1:1234",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "",
+			},
+		},
+		{
+			name: "positive case: head",
+			args: args{
+				method:     http.MethodHead,
+				target:     "/meta-data",
+				remoteAddr: "192.0.2.1:1234",
+			},
+			expected: expected{
+				code: http.StatusOK,
+				body: "",
+			},
+		},
+		{
+			name: "negative case: missing vendor-data",
+			args: args{
+				method:     http.MethodGet,
+				target:     "/vendor-data",
+				remoteAddr: "192.0.2.1:1234",
+			},
+			expected: expected{
+				code: http.StatusNotFound,
+				body: "404 page not found\n",
+			},
+		},
+		{
+			name: "negative case: method not allowed",
+			args: args{
+				method:     http.MethodPost,
+				target:     "/meta-data",
+				remoteAddr: "192.0.2.1:1234",
+			},
+			expected: expected{
+				code: http.StatusMethodNotAllowed,
+				body: "Method Not Allowed\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.args.method, tt.args.target, nil)
+			req.RemoteAddr = tt.args.remoteAddr
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expected.code, rec.Code)
+			assert.Equal(t, tt.expected.body, rec.Body.String())
+		})
+	}
+}
+
+func TestSeedHandler_SetMACSeed(t *testing.T) {
+	handler := NewSeedHandler(nil)
+
+	assert.NoError(t, handler.SetMACSeed("52:54:00:12:34:56", &Seed{}))
+	assert.Equal(t, &Error{Op: "register", Err: ErrInvalidMACAddress}, handler.SetMACSeed("52:54:00:12:34", &Seed{}))
+}
+
+func TestSeedHandler_SetIPSeed(t *testing.T) {
+	handler := NewSeedHandler(nil)
+
+	assert.NoError(t, handler.SetIPSeed("2001:db8::1", &Seed{}))
+	assert.Equal(t, &Error{Op: "register", Err: ErrInvalidIPAddress}, handler.SetIPSeed("192.0.2", &Seed{}))
+}
+
+func TestNoCloudNetKernelCmdline(t *testing.T) {
+	type args struct {
+		seedURL string
+	}
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case",
+			args: args{
+				seedURL: "http://10.0.2.2:8000/seed/",
+			},
+			expected: expected{
+				res: "ds=nocloud-net;s=http://10.0.2.2:8000/seed/",
+			},
+		},
+		{
+			name: "positive case: trailing slash added",
+			args: args{
+				seedURL: "https://seed.example.com/seed",
+			},
+			expected: expected{
+				res: "ds=nocloud-net;s=https://seed.example.com/seed/",
+			},
+		},
+		{
+			name: "negative case: unsupported scheme",
+			args: args{
+				seedURL: "ftp://seed.example.com/seed/",
+			},
+			expected: expected{
+				err: &Error{Op: "parse", Err: ErrInvalidURL},
+			},
+		},
+		{
+			name: "negative case: semicolon",
+			args: args{
+				seedURL: "http://seed.example.com/a;b/",
+			},
+			expected: expected{
+				err: &Error{Op: "parse", Err: ErrInvalidURL},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NoCloudNetKernelCmdline(tt.args.seedURL)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestNoCloudNetSMBIOS(t *testing.T) {
+	type args struct {
+		seedURL string
+	}
+
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     args
+		expected expected
+	}{
+		{
+			name: "positive case",
+			args: args{
+				seedURL: "http://10.0.2.2:8000/",
+			},
+			expected: expected{
+				res: "type=1,serial=ds=nocloud-net;s=http://10.0.2.2:8000/",
+			},
+		},
+		{
+			name: "positive case: escaped comma",
+			args: args{
+				seedURL: "http://10.0.2.2:8000/a,b/",
+			},
+			expected: expected{
+				res: "type=1,serial=ds=nocloud-net;s=http://10.0.2.2:8000/a,,b/",
+			},
+		},
+		{
+			name: "negative case: missing host",
+			args: args{
+				seedURL: "http:///seed/",
+			},
+			expected: expected{
+				err: &Error{Op: "parse", Err: ErrInvalidURL},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NoCloudNetSMBIOS(tt.args.seedURL)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type FileSink interface {
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+type dirSink struct {
+	dir string
+}
+
+func NewDirSink(dir string) FileSink {
+	return &dirSink{dir: dir}
+}
+
+func (s *dirSink) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		err := &Error{Op: "write", Err: ErrInvalidPath}
+		logger.Println("failed to write file", "func", getFuncName(), "name", name, "error", err)
+		return err
+	}
+
+	path := filepath.Join(s.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		err = &Error{Op: "write", Err: err}
+		logger.Println("failed to write file", "func", getFuncName(), "name", name, "error", err)
+		return err
+	}
+
+	if err := os.WriteFile(path, data, perm); err != nil {
+		err = &Error{Op: "write", Err: err}
+		logger.Println("failed to write file", "func", getFuncName(), "name", name, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+type fileNode struct {
+	name     string
+	mode     fs.FileMode
+	data     []byte
+	children map[string]*fileNode
+}
+
+func newFileTree() *fileNode {
+	return &fileNode{mode: fs.ModeDir | 0o755, children: make(map[string]*fileNode)}
+}
+
+func (n *fileNode) writeFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return ErrInvalidPath
+	}
+
+	dir := n
+	elems := strings.Split(name, "/")
+	for _, elem := range elems[:len(elems)-1] {
+		child, ok := dir.children[elem]
+		if !ok {
+			child = &fileNode{name: elem, mode: fs.ModeDir | 0o755, children: make(map[string]*fileNode)}
+			dir.children[elem] = child
+		}
+
+		if !child.mode.IsDir() {
+			return ErrInvalidPath
+		}
+
+		dir = child
+	}
+
+	base := elems[len(elems)-1]
+	if child, ok := dir.children[base]; ok && child.mode.IsDir() {
+		return ErrInvalidPath
+	}
+
+	dir.children[base] = &fileNode{name: base, mode: perm.Perm(), data: slices.Clone(data)}
+
+	return nil
+}
+
+func (n *fileNode) sortedChildren() []*fileNode {
+	names := maps.Keys(n.children)
+	slices.Sort(names)
+
+	children := make([]*fileNode, 0, len(names))
+	for _, name := range names {
+		children = append(children, n.children[name])
+	}
+
+	return children
+}
@@ -0,0 +1,388 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+type WindowsShell string
+
+const (
+	WindowsShellPowerShell    WindowsShell = "powershell"
+	WindowsShellPowerShellX86 WindowsShell = "powershell-x86"
+	WindowsShellCmd           WindowsShell = "cmd"
+)
+
+type EC2LaunchFrequency string
+
+const (
+	EC2LaunchFrequencyOnce   EC2LaunchFrequency = "once"
+	EC2LaunchFrequencyAlways EC2LaunchFrequency = "always"
+)
+
+type EC2LaunchScriptType string
+
+const (
+	EC2LaunchScriptTypePowerShell EC2LaunchScriptType = "powershell"
+	EC2LaunchScriptTypeBatch      EC2LaunchScriptType = "batch"
+)
+
+type EC2LaunchRunAs string
+
+const (
+	EC2LaunchRunAsLocalSystem EC2LaunchRunAs = "localSystem"
+	EC2LaunchRunAsAdmin       EC2LaunchRunAs = "admin"
+)
+
+const (
+	ec2LaunchDefaultVersion = "1.1"
+	ec2LaunchExecuteScript  = "executeScript"
+)
+
+var (
+	windowsShellMarkers = map[WindowsShell]string{
+		WindowsShellPowerShell:    "#ps1_sysnative",
+		WindowsShellPowerShellX86: "#ps1_x86",
+		WindowsShellCmd:           "rem cmd",
+	}
+
+	// cloudbase-init picks the interpreter of a multipart script from its file extension
+	windowsShellExtensions = map[WindowsShell]string{
+		WindowsShellPowerShell:    ".ps1",
+		WindowsShellPowerShellX86: ".ps1",
+		WindowsShellCmd:           ".cmd",
+	}
+
+	ec2LaunchVersions = []string{"1.0", "1.1"}
+)
+
+type WindowsScript struct {
+	Shell    WindowsShell
+	Filename string
+	Body     []byte
+}
+
+type EC2LaunchDocument struct {
+	Version string          `yaml:"version"`
+	Tasks   []EC2LaunchTask `yaml:"tasks"`
+}
+
+type EC2LaunchTask struct {
+	Task   string `yaml:"task"`
+	Inputs any    `yaml:"inputs,omitempty"`
+}
+
+type EC2LaunchScript struct {
+	Frequency EC2LaunchFrequency  `yaml:"frequency"`
+	Type      EC2LaunchScriptType `yaml:"type"`
+	RunAs     EC2LaunchRunAs      `yaml:"runAs,omitempty"`
+	Content   string              `yaml:"content"`
+	Detach    bool                `yaml:"detach,omitempty"`
+}
+
+type EC2LaunchTags struct {
+	PowerShell []byte
+	Batch      []byte
+	Persist    bool
+}
+
+func (s *WindowsScript) Build() (Part, error) {
+	shell, body, err := s.split()
+	if err != nil {
+		logger.Println("failed to build windows script", "func", getFuncName(), "script", s, "error", err)
+		return nil, err
+	}
+
+	filename := s.Filename
+	if filename == "" {
+		filename = "script" + windowsShellExtensions[shell]
+	}
+
+	if !strings.EqualFold(path.Ext(filename), windowsShellExtensions[shell]) {
+		err := &Error{Op: "build", Err: ErrInvalidName}
+		logger.Println("failed to build windows script", "func", getFuncName(), "script", s, "error", err)
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(windowsShellMarkers[shell])
+	buf.WriteString("\r\n")
+	buf.Write(crlf(body))
+
+	return NewPartWithFilename(MediaTypeXShellscript, buf.Bytes(), filename)
+}
+
+func (s *WindowsScript) split() (WindowsShell, []byte, error) {
+	marked, body := splitWindowsMarker(s.Body)
+
+	if s.Shell == "" {
+		if marked == "" {
+			return "", nil, &Error{Op: "build", Err: ErrMissingMarker}
+		}
+
+		return marked, body, nil
+	}
+
+	if _, ok := windowsShellMarkers[s.Shell]; !ok {
+		return "", nil, &Error{Op: "build", Err: ErrInvalidType}
+	}
+
+	if marked != "" && marked != s.Shell {
+		return "", nil, &Error{Op: "build", Err: ErrInvalidShebang}
+	}
+
+	return s.Shell, body, nil
+}
+
+func NewEC2LaunchExecuteScript(scripts ...EC2LaunchScript) EC2LaunchTask {
+	return EC2LaunchTask{Task: ec2LaunchExecuteScript, Inputs: scripts}
+}
+
+func NewEC2LaunchDocument(m Multipart) (*EC2LaunchDocument, error) {
+	scripts := make([]EC2LaunchScript, 0)
+	for _, p := range m.Parts() {
+		if !p.MediaType().IsScript() {
+			err := &Error{Op: "convert", Err: ErrUnsupportedMediaType}
+			logger.Println("failed to convert to ec2launch document", "func", getFuncName(), "mediaType", p.MediaType(), "error", err)
+			return nil, err
+		}
+
+		shell, body := splitWindowsMarker(p.Body())
+		if shell == "" {
+			shell = windowsShellFromFilename(p.Filename())
+		}
+
+		var typ EC2LaunchScriptType
+		switch shell {
+		case WindowsShellPowerShell, WindowsShellPowerShellX86:
+			typ = EC2LaunchScriptTypePowerShell
+		case WindowsShellCmd:
+			typ = EC2LaunchScriptTypeBatch
+		default:
+			err := &Error{Op: "convert", Err: ErrMissingMarker}
+			logger.Println("failed to convert to ec2launch document", "func", getFuncName(), "mediaType", p.MediaType(), "error", err)
+			return nil, err
+		}
+
+		freq := EC2LaunchFrequencyOnce
+		if f := p.MediaType().Frequency(); f == FrequencyAlways || f == FrequencyPerBoot {
+			freq = EC2LaunchFrequencyAlways
+		}
+
+		// yaml.v3 would double-quote content with carriage returns
+		content := strings.ReplaceAll(string(body), "\r\n", "\n")
+		scripts = append(scripts, EC2LaunchScript{Frequency: freq, Type: typ, Content: content})
+	}
+
+	doc := &EC2LaunchDocument{Version: ec2LaunchDefaultVersion}
+	if len(scripts) > 0 {
+		doc.Tasks = []EC2LaunchTask{NewEC2LaunchExecuteScript(scripts...)}
+	}
+
+	return doc, nil
+}
+
+func (d *EC2LaunchDocument) Render(w io.Writer) error {
+	if err := d.validate(); err != nil {
+		err = &Error{Op: "validate", Err: err}
+		logger.Println("failed to render ec2launch document", "func", getFuncName(), "document", d, "error", err)
+		return err
+	}
+
+	doc := *d
+	if doc.Version == "" {
+		doc.Version = ec2LaunchDefaultVersion
+	}
+
+	if doc.Tasks == nil {
+		doc.Tasks = make([]EC2LaunchTask, 0)
+	}
+
+	if err := renderYAML(w, &doc); err != nil {
+		logger.Println("failed to render ec2launch document", "func", getFuncName(), "document", d, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (d *EC2LaunchDocument) Encode() (string, error) {
+	b, err := renderBytes(d)
+	if err != nil {
+		logger.Println("failed to encode ec2launch document", "func", getFuncName(), "error", err)
+		return "", err
+	}
+
+	return encodeEC2Launch(b)
+}
+
+func (d *EC2LaunchDocument) validate() error {
+	if d.Version != "" && !slices.Contains(ec2LaunchVersions, d.Version) {
+		return ErrInvalidVersion
+	}
+
+	for _, task := range d.Tasks {
+		if task.Task == "" {
+			return ErrMissingTaskName
+		}
+
+		scripts, ok := task.Inputs.([]EC2LaunchScript)
+		if !ok {
+			continue
+		}
+
+		for _, s := range scripts {
+			switch s.Frequency {
+			case EC2LaunchFrequencyOnce, EC2LaunchFrequencyAlways:
+			default:
+				return ErrInvalidFrequency
+			}
+
+			switch s.Type {
+			case EC2LaunchScriptTypePowerShell, EC2LaunchScriptTypeBatch:
+			default:
+				return ErrInvalidType
+			}
+
+			switch s.RunAs {
+			case "", EC2LaunchRunAsLocalSystem, EC2LaunchRunAsAdmin:
+			default:
+				return ErrInvalidRunAs
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *EC2LaunchTags) Render(w io.Writer) error {
+	if err := t.validate(); err != nil {
+		err = &Error{Op: "validate", Err: err}
+		logger.Println("failed to render ec2launch tags", "func", getFuncName(), "tags", t, "error", err)
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	for _, tag := range []struct {
+		name string
+		body []byte
+	}{
+		{name: "script", body: t.Batch},
+		{name: "powershell", body: t.PowerShell},
+	} {
+		if len(tag.body) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(buf, "<%s>\n", tag.name)
+		buf.Write(tag.body)
+		if !bytes.HasSuffix(tag.body, []byte("\n")) {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "</%s>\n", tag.name)
+	}
+
+	if t.Persist {
+		fmt.Fprint(buf, "<persist>true</persist>\n")
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		err = &Error{Op: "render", Err: err}
+		logger.Println("failed to render ec2launch tags", "func", getFuncName(), "tags", t, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (t *EC2LaunchTags) Encode() (string, error) {
+	b, err := renderBytes(t)
+	if err != nil {
+		logger.Println("failed to encode ec2launch tags", "func", getFuncName(), "error", err)
+		return "", err
+	}
+
+	return encodeEC2Launch(b)
+}
+
+func (t *EC2LaunchTags) validate() error {
+	if len(t.PowerShell) == 0 && len(t.Batch) == 0 {
+		return ErrInvalidBody
+	}
+
+	if bytes.Contains(bytes.ToLower(t.Batch), []byte("</script>")) || bytes.Contains(bytes.ToLower(t.PowerShell), []byte("</powershell>")) {
+		return ErrInvalidBody
+	}
+
+	return nil
+}
+
+// ec2launch does not decompress user data, so unlike EC2UserData it is never gzipped
+func encodeEC2Launch(b []byte) (string, error) {
+	if len(b) > ec2MaxUserDataSize {
+		err := &Error{Op: "encode", Err: ErrPayloadTooLarge}
+		logger.Println("failed to encode ec2launch user data", "func", getFuncName(), "size", len(b), "error", err)
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func splitWindowsMarker(body []byte) (WindowsShell, []byte) {
+	line, rest, _ := bytes.Cut(body, []byte("\n"))
+	marker := strings.ToLower(strings.TrimSpace(string(line)))
+
+	for shell, m := range windowsShellMarkers {
+		if marker == m {
+			return shell, rest
+		}
+	}
+
+	return "", body
+}
+
+func isWindowsScript(p Part) bool {
+	shell, _ := splitWindowsMarker(p.Body())
+	return shell != "" || windowsShellFromFilename(p.Filename()) != ""
+}
+
+func windowsShellFromFilename(filename string) WindowsShell {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".ps1":
+		return WindowsShellPowerShell
+	case ".cmd", ".bat":
+		return WindowsShellCmd
+	default:
+		return ""
+	}
+}
+
+func crlf(b []byte) []byte {
+	return bytes.ReplaceAll(bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n")), []byte("\n"), []byte("\r\n"))
+}
@@ -0,0 +1,406 @@
+// Copyright (c) 2026 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package userdata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowsScript_Build(t *testing.T) {
+	type expected struct {
+		body     []byte
+		filename string
+		err      error
+	}
+
+	tests := []struct {
+		name     string
+		script   WindowsScript
+		expected expected
+	}{
+		{
+			name: "positive case: powershell",
+			script: WindowsScript{
+				Shell: WindowsShellPowerShell,
+				Body:  []byte("Write-Host 'Hello'\nWrite-Host 'World'\n"),
+			},
+			expected: expected{
+				body:     []byte("#ps1_sysnative\r\nWrite-Host 'Hello'\r\nWrite-Host 'World'\r\n"),
+				filename: "script.ps1",
+			},
+		},
+		{
+			name: "positive case: powershell x86 with filename",
+			script: WindowsScript{
+				Shell:    WindowsShellPowerShellX86,
+				Filename: "Setup.PS1",
+				Body:     []byte("Write-Host 'Hello World'\r\n"),
+			},
+			expected: expected{
+				body:     []byte("#ps1_x86\r\nWrite-Host 'Hello World'\r\n"),
+				filename: "Setup.PS1",
+			},
+		},
+		{
+			name: "positive case: cmd detected from marker",
+			script: WindowsScript{
+				Body: []byte("REM CMD\necho Hello World\n"),
+			},
+			expected: expected{
+				body:     []byte("rem cmd\r\necho Hello World\r\n"),
+				filename: "script.cmd",
+			},
+		},
+		{
+			name: "negative case: missing marker",
+			script: WindowsScript{
+				Body: []byte("Write-Host 'Hello World'\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrMissingMarker},
+			},
+		},
+		{
+			name: "negative case: marker mismatch",
+			script: WindowsScript{
+				Shell: WindowsShellCmd,
+				Body:  []byte("#ps1_sysnative\nWrite-Host 'Hello World'\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidShebang},
+			},
+		},
+		{
+			name: "negative case: invalid shell",
+			script: WindowsScript{
+				Shell: "bash",
+				Body:  []byte("echo 'Hello World'\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidType},
+			},
+		},
+		{
+			name: "negative case: extension mismatch",
+			script: WindowsScript{
+				Shell:    WindowsShellPowerShell,
+				Filename: "setup.cmd",
+				Body:     []byte("Write-Host 'Hello World'\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "build", Err: ErrInvalidName},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.script.Build()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, MediaTypeXShellscript, actual.MediaType())
+				assert.Equal(t, tt.expected.body, actual.Body())
+				assert.Equal(t, tt.expected.filename, actual.Filename())
+				assert.Empty(t, Lint(actual))
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestNewEC2LaunchDocument(t *testing.T) {
+	mustBuild := func(s WindowsScript) Part {
+		p, err := s.Build()
+		if err != nil {
+			panic(err)
+		}
+		return p
+	}
+
+	filenamePart, _ := NewPartWithFilename(MediaTypeXShellscriptPerBoot, []byte("echo Hello World\n"), "setup.bat")
+
+	type expected struct {
+		res *EC2LaunchDocument
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		userData Multipart
+		expected expected
+	}{
+		{
+			name: "positive case",
+			userData: mustNewMultipart(
+				mustBuild(WindowsScript{Shell: WindowsShellPowerShell, Body: []byte("Write-Host 'Hello World'\n")}),
+				filenamePart,
+			),
+			expected: expected{
+				res: &EC2LaunchDocument{
+					Version: "1.1",
+					Tasks: []EC2LaunchTask{
+						{
+							Task: "executeScript",
+							Inputs: []EC2LaunchScript{
+								{Frequency: EC2LaunchFrequencyOnce, Type: EC2LaunchScriptTypePowerShell, Content: "Write-Host 'Hello World'\n"},
+								{Frequency: EC2LaunchFrequencyAlways, Type: EC2LaunchScriptTypeBatch, Content: "echo Hello World\n"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "positive case: empty",
+			userData: mustNewMultipart(),
+			expected: expected{
+				res: &EC2LaunchDocument{Version: "1.1"},
+			},
+		},
+		{
+			name: "negative case: not a script",
+			userData: mustNewMultipart(
+				mustNewPart(MediaTypeCloudConfig, []byte("#cloud-config\n")),
+			),
+			expected: expected{
+				err: &Error{Op: "convert", Err: ErrUnsupportedMediaType},
+			},
+		},
+		{
+			name: "negative case: linux script",
+			userData: mustNewMultipart(
+				mustNewPart(MediaTypeXShellscript, []byte("#!/bin/bash\necho 'Hello World'\n")),
+			),
+			expected: expected{
+				err: &Error{Op: "convert", Err: ErrMissingMarker},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewEC2LaunchDocument(tt.userData)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, actual)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestEC2LaunchDocument_Render(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		document EC2LaunchDocument
+		expected expected
+	}{
+		{
+			name: "positive case",
+			document: EC2LaunchDocument{
+				Tasks: []EC2LaunchTask{
+					NewEC2LaunchExecuteScript(EC2LaunchScript{
+						Frequency: EC2LaunchFrequencyOnce,
+						Type:      EC2LaunchScriptTypePowerShell,
+						RunAs:     EC2LaunchRunAsLocalSystem,
+						Content:   "New-Item -Path 'C:\\Test.txt' -ItemType File\n",
+					}),
+					{Task: "enableOpenSsh"},
+				},
+			},
+			expected: expected{
+				res: "version: \"1.1\"\n" +
+					"tasks:\n" +
+					"  - task: executeScript\n" +
+					"    inputs:\n" +
+					"      - frequency: once\n" +
+					"        type: powershell\n" +
+					"        runAs: localSystem\n" +
+					"        content: |\n" +
+					"          New-Item -Path 'C:\\Test.txt' -ItemType File\n" +
+					"  - task: enableOpenSsh\n",
+			},
+		},
+		{
+			name:     "positive case: empty",
+			document: EC2LaunchDocument{Version: "1.0"},
+			expected: expected{
+				res: "version: \"1.0\"\n" +
+					"tasks: []\n",
+			},
+		},
+		{
+			name:     "negative case: invalid version",
+			document: EC2LaunchDocument{Version: "2.0"},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidVersion},
+			},
+		},
+		{
+			name:     "negative case: missing task name",
+			document: EC2LaunchDocument{Tasks: []EC2LaunchTask{{}}},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrMissingTaskName},
+			},
+		},
+		{
+			name: "negative case: invalid frequency",
+			document: EC2LaunchDocument{
+				Tasks: []EC2LaunchTask{
+					NewEC2LaunchExecuteScript(EC2LaunchScript{Frequency: "per-boot", Type: EC2LaunchScriptTypeBatch}),
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidFrequency},
+			},
+		},
+		{
+			name: "negative case: invalid type",
+			document: EC2LaunchDocument{
+				Tasks: []EC2LaunchTask{
+					NewEC2LaunchExecuteScript(EC2LaunchScript{Frequency: EC2LaunchFrequencyOnce, Type: "bash"}),
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidType},
+			},
+		},
+		{
+			name: "negative case: invalid run as",
+			document: EC2LaunchDocument{
+				Tasks: []EC2LaunchTask{
+					NewEC2LaunchExecuteScript(EC2LaunchScript{Frequency: EC2LaunchFrequencyOnce, Type: EC2LaunchScriptTypeBatch, RunAs: "root"}),
+				},
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidRunAs},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := new(bytes.Buffer)
+			err := tt.document.Render(w)
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.res, w.String())
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestEC2LaunchTags_Encode(t *testing.T) {
+	type expected struct {
+		res string
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		tags     EC2LaunchTags
+		expected expected
+	}{
+		{
+			name: "positive case: powershell",
+			tags: EC2LaunchTags{
+				PowerShell: []byte("Write-Host 'Hello World'"),
+				Persist:    true,
+			},
+			expected: expected{
+				res: "<powershell>\n" +
+					"Write-Host 'Hello World'\n" +
+					"</powershell>\n" +
+					"<persist>true</persist>\n",
+			},
+		},
+		{
+			name: "positive case: batch and powershell",
+			tags: EC2LaunchTags{
+				PowerShell: []byte("Write-Host 'Hello World'\n"),
+				Batch:      []byte("echo Hello World\n"),
+			},
+			expected: expected{
+				res: "<script>\n" +
+					"echo Hello World\n" +
+					"</script>\n" +
+					"<powershell>\n" +
+					"Write-Host 'Hello World'\n" +
+					"</powershell>\n",
+			},
+		},
+		{
+			name: "negative case: empty",
+			tags: EC2LaunchTags{},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidBody},
+			},
+		},
+		{
+			name: "negative case: closing tag in body",
+			tags: EC2LaunchTags{
+				PowerShell: []byte("Write-Host '</PowerShell>'\n"),
+			},
+			expected: expected{
+				err: &Error{Op: "validate", Err: ErrInvalidBody},
+			},
+		},
+		{
+			name: "negative case: too large",
+			tags: EC2LaunchTags{
+				PowerShell: []byte(strings.Repeat("#", 16*1024)),
+			},
+			expected: expected{
+				err: &Error{Op: "encode", Err: ErrPayloadTooLarge},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.tags.Encode()
+
+			if tt.expected.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, base64.StdEncoding.EncodeToString([]byte(tt.expected.res)), actual)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}